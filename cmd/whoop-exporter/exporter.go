@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/export"
+)
+
+// requestsPerPoll is the number of API requests made to poll a user.
+const requestsPerPoll = 3
+
+// now returns the current time. It is replaced in tests.
+var now = time.Now
+
+// user is a member whose metrics are exported.
+type user struct {
+	name   string
+	client *whoop.Client
+}
+
+// userState holds the latest metrics and the polling health of a user.
+type userState struct {
+	points      map[string]export.Point // Latest scored point by measurement.
+	lastScrape  time.Time               // Time of the last poll attempt.
+	lastSuccess time.Time               // Time of the last poll without errors.
+	errors      map[string]int          // API errors by kind.
+	skipped     int                     // Polls skipped because of the rate limit.
+	rate        whoop.Rate
+}
+
+// exporter polls the latest cycle, recovery and sleep of its users and
+// serves them in the Prometheus text exposition format.
+type exporter struct {
+	users  []user
+	logger *slog.Logger
+
+	mu    sync.Mutex
+	state map[string]*userState
+}
+
+func newExporter(users []user, logger *slog.Logger) *exporter {
+	e := &exporter{users: users, logger: logger, state: map[string]*userState{}}
+	for _, u := range users {
+		e.state[u.name] = &userState{points: map[string]export.Point{}, errors: map[string]int{}}
+	}
+	return e
+}
+
+// run polls every user each interval until ctx is done.
+func (e *exporter) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll polls every user once. Users whose remaining rate limit cannot
+// cover a poll are skipped until the limit resets.
+func (e *exporter) poll(ctx context.Context) {
+	for _, u := range e.users {
+		if ctx.Err() != nil {
+			return
+		}
+		if rate := u.client.Rate(); rate.Remaining < requestsPerPoll && now().Before(rate.Reset) {
+			e.logger.Warn("rate limit reached, skipping user", "user", u.name, "rate_remaining", rate.Remaining, "rate_reset", rate.Reset)
+			e.update(u, nil, nil, true)
+			continue
+		}
+		points, errs := fetch(ctx, u.client)
+		for _, err := range errs {
+			e.logger.Error("polling failed", "user", u.name, "error", err)
+		}
+		e.update(u, points, errs, false)
+	}
+}
+
+func (e *exporter) update(u user, points []export.Point, errs []error, skipped bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	s := e.state[u.name]
+	s.rate = u.client.Rate()
+	if skipped {
+		s.skipped++
+		return
+	}
+	s.lastScrape = now()
+	if len(errs) == 0 {
+		s.lastSuccess = s.lastScrape
+	}
+	for _, p := range points {
+		s.points[p.Measurement] = p
+	}
+	for _, err := range errs {
+		s.errors[errorKind(err)]++
+	}
+}
+
+// fetch returns the points of the latest scored cycle, recovery and sleep
+// of client's user, skipping records which are not scored yet.
+func fetch(ctx context.Context, client *whoop.Client) ([]export.Point, []error) {
+	var points []export.Point
+	var errs []error
+	add := func(p export.Point, ok bool) {
+		if ok {
+			points = append(points, p)
+		}
+	}
+
+	if resp, err := client.Cycle.ListAll(ctx, &whoop.RequestParams{Limit: 1}); err != nil {
+		errs = append(errs, fmt.Errorf("cycles: %w", err))
+	} else if len(resp.Records) > 0 {
+		add(export.CyclePoint(&resp.Records[0]))
+	}
+	if resp, err := client.Recovery.ListAll(ctx, &whoop.RequestParams{Limit: 1}); err != nil {
+		errs = append(errs, fmt.Errorf("recoveries: %w", err))
+	} else if len(resp.Records) > 0 {
+		add(export.RecoveryPoint(&resp.Records[0]))
+	}
+	// Fetch a few sleeps so that a nap does not hide the latest night.
+	if resp, err := client.Sleep.ListAll(ctx, &whoop.RequestParams{Limit: 5}); err != nil {
+		errs = append(errs, fmt.Errorf("sleeps: %w", err))
+	} else {
+		for i := range resp.Records {
+			if !resp.Records[i].Nap {
+				add(export.SleepPoint(&resp.Records[i]))
+				break
+			}
+		}
+	}
+	return points, errs
+}
+
+// errorKind classifies err for the api_errors_total metric.
+func errorKind(err error) string {
+	var rateErr *whoop.RateLimitError
+	switch {
+	case errors.As(err, &rateErr):
+		return "rate_limited"
+	case errors.Is(err, whoop.ErrUnauthorized), errors.Is(err, whoop.ErrForbidden):
+		return "unauthorized"
+	case errors.Is(err, whoop.ErrServer):
+		return "server"
+	case errors.Is(err, whoop.ErrBadRequest), errors.Is(err, whoop.ErrNotFound):
+		return "client"
+	default:
+		return "other"
+	}
+}
+
+// metric is a metric family of the exposition.
+type metric struct {
+	help, typ string
+	samples   []string
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.writeMetrics(w)
+}
+
+func (e *exporter) writeMetrics(w io.Writer) {
+	families := map[string]*metric{}
+	add := func(name, help, typ string, labels []export.Tag, value float64) {
+		m, ok := families[name]
+		if !ok {
+			m = &metric{help: help, typ: typ}
+			families[name] = m
+		}
+		m.samples = append(m.samples, name+formatLabels(labels)+" "+strconv.FormatFloat(value, 'g', -1, 64))
+	}
+	timestamp := func(t time.Time) float64 {
+		if t.IsZero() {
+			return 0
+		}
+		return float64(t.UnixMilli()) / 1000
+	}
+
+	e.mu.Lock()
+	for _, u := range e.users {
+		s := e.state[u.name]
+		userLabel := []export.Tag{{Key: "user", Value: u.name}}
+		for _, p := range s.points {
+			labels := append(append([]export.Tag{}, userLabel...), p.Tags...)
+			for _, f := range p.Fields {
+				name := p.Measurement + "_" + f.Key
+				add(name, fmt.Sprintf("Latest scored %v %v.", strings.TrimPrefix(p.Measurement, "whoop_"), strings.ReplaceAll(f.Key, "_", " ")), "gauge", labels, f.Value)
+			}
+			add("whoop_record_timestamp_seconds", "Time of the latest scored record.", "gauge",
+				append(append([]export.Tag{}, userLabel...), export.Tag{Key: "type", Value: strings.TrimPrefix(p.Measurement, "whoop_")}), timestamp(p.Time))
+		}
+		add("whoop_exporter_last_scrape_timestamp_seconds", "Time of the last poll of the API.", "gauge", userLabel, timestamp(s.lastScrape))
+		add("whoop_exporter_last_success_timestamp_seconds", "Time of the last poll of the API without errors.", "gauge", userLabel, timestamp(s.lastSuccess))
+		add("whoop_exporter_skipped_polls_total", "Polls skipped because the rate limit was reached.", "counter", userLabel, float64(s.skipped))
+		add("whoop_exporter_rate_remaining", "Remaining API requests in the current rate limit window.", "gauge", userLabel, float64(s.rate.Remaining))
+		add("whoop_exporter_rate_reset_timestamp_seconds", "Time at which the rate limit window resets.", "gauge", userLabel, timestamp(s.rate.Reset))
+		kinds := make([]string, 0, len(s.errors))
+		for kind := range s.errors {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			add("whoop_exporter_api_errors_total", "API errors by kind.", "counter",
+				append(append([]export.Tag{}, userLabel...), export.Tag{Key: "kind", Value: kind}), float64(s.errors[kind]))
+		}
+	}
+	e.mu.Unlock()
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		m := families[name]
+		sort.Strings(m.samples)
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, m.help, name, m.typ)
+		for _, s := range m.samples {
+			fmt.Fprintln(w, s)
+		}
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func formatLabels(labels []export.Tag) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.Key + `="` + labelEscaper.Replace(l.Value) + `"`
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestExporter(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 11, 28, 0, 0, 0, 0, time.UTC) }
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "97")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/cycle"):
+			fmt.Fprint(w, `{"records": [{"id": 1, "user_id": 10, "start": "2022-11-27T08:00:00Z", "score_state": "SCORED", "score": {"strain": 8.5}}]}`)
+		case strings.HasSuffix(r.URL.Path, "/recovery"):
+			fmt.Fprint(w, `{"records": [{"cycle_id": 1, "user_id": 10, "created_at": "2022-11-27T08:30:00Z", "score_state": "SCORED", "score": {"recovery_score": 34}}]}`)
+		case strings.HasSuffix(r.URL.Path, "/sleep"):
+			fmt.Fprint(w, `{"records": [
+				{"id": 3, "user_id": 10, "start": "2022-11-27T20:00:00Z", "nap": true, "score_state": "SCORED", "score": {"sleep_performance_percentage": 10}},
+				{"id": 2, "user_id": 10, "start": "2022-11-27T00:00:00Z", "score_state": "SCORED", "score": {"sleep_performance_percentage": 91}}
+			]}`)
+		}
+	}))
+
+	e := newExporter([]user{{name: "alice", client: client}}, discard)
+	e.poll(context.Background())
+
+	var buf bytes.Buffer
+	e.writeMetrics(&buf)
+	got := buf.String()
+	for _, want := range []string{
+		"# TYPE whoop_recovery_score gauge\n",
+		`whoop_recovery_score{user="alice",user_id="10"} 34`,
+		`whoop_cycle_strain{user="alice",user_id="10"} 8.5`,
+		`whoop_sleep_performance_percentage{user="alice",user_id="10"} 91`,
+		`whoop_record_timestamp_seconds{user="alice",type="cycle"} 1.669536e+09`,
+		`whoop_exporter_last_success_timestamp_seconds{user="alice"} 1.6695936e+09`,
+		`whoop_exporter_rate_remaining{user="alice"} 97`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeMetrics(): expected output to contain %q, got\n%v", want, got)
+		}
+	}
+}
+
+func TestExporter_errors(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 11, 28, 0, 0, 0, 0, time.UTC) }
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	e := newExporter([]user{{name: "bob", client: client}}, discard)
+	e.poll(context.Background())
+
+	var buf bytes.Buffer
+	e.writeMetrics(&buf)
+	got := buf.String()
+	for _, want := range []string{
+		`whoop_exporter_api_errors_total{user="bob",kind="unauthorized"} 3`,
+		`whoop_exporter_last_scrape_timestamp_seconds{user="bob"} 1.6695936e+09`,
+		`whoop_exporter_last_success_timestamp_seconds{user="bob"} 0`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeMetrics(): expected output to contain %q, got\n%v", want, got)
+		}
+	}
+}
+
+func TestExporter_rateLimit(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 11, 28, 0, 0, 0, 0, time.UTC) }
+	requests := 0
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-RateLimit-Remaining", "2")
+		w.Header().Set("X-RateLimit-Reset", "60")
+		fmt.Fprint(w, `{"records": []}`)
+	}))
+
+	e := newExporter([]user{{name: "carol", client: client}}, discard)
+	e.poll(context.Background())
+	e.poll(context.Background())
+
+	if requests != requestsPerPoll {
+		t.Errorf("poll(): expected %v requests, got %v", requestsPerPoll, requests)
+	}
+	var buf bytes.Buffer
+	e.writeMetrics(&buf)
+	if want := `whoop_exporter_skipped_polls_total{user="carol"} 1`; !strings.Contains(buf.String(), want) {
+		t.Errorf("writeMetrics(): expected output to contain %q, got\n%v", want, buf.String())
+	}
+}
+
+func TestLoadUsers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	t.Setenv("BOB_TOKEN", "secret")
+	os.WriteFile(path, []byte(`{"users": [{"name": "alice", "token": "a"}, {"name": "bob", "token_env": "BOB_TOKEN"}]}`), 0o600)
+	users, err := loadUsers(path, discard)
+	if err != nil {
+		t.Fatalf("loadUsers(): expected nil error, got %v", err)
+	}
+	if len(users) != 2 || users[0].name != "alice" || users[1].name != "bob" {
+		t.Errorf("loadUsers(): got %v", users)
+	}
+
+	os.WriteFile(path, []byte(`{"users": [{"name": "carol", "token_env": "UNSET_TOKEN"}]}`), 0o600)
+	if _, err := loadUsers(path, discard); err == nil {
+		t.Error("loadUsers(): expected error for missing token, got nil")
+	}
+}
@@ -0,0 +1,144 @@
+// Command whoop-exporter is a Prometheus exporter of the latest WHOOP
+// metrics of a set of members.
+//
+// It periodically polls each configured user's latest cycle, recovery and
+// sleep, and exposes their scored metrics on /metrics as gauges, together
+// with the health of the exporter: the time of the last poll, API errors
+// and the remaining rate limit. Users whose remaining rate limit cannot
+// cover a poll are skipped until the limit resets.
+//
+// Usage:
+//
+//	whoop-exporter -config users.json [-listen :9876] [-interval 15m]
+//
+// The configuration file lists the users to poll with their OAuth2 access
+// tokens, given inline or as the name of an environment variable:
+//
+//	{
+//	  "users": [
+//	    {"name": "alice", "token": "..."},
+//	    {"name": "bob", "token_env": "BOB_WHOOP_TOKEN"}
+//	  ]
+//	}
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// config is the configuration file of the exporter.
+type config struct {
+	Users []struct {
+		Name     string `json:"name"`
+		Token    string `json:"token"`
+		TokenEnv string `json:"token_env"`
+	} `json:"users"`
+}
+
+// loadUsers reads the configuration file at path and returns a client
+// for each configured user.
+func loadUsers(path string, logger *slog.Logger) ([]user, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	if len(cfg.Users) == 0 {
+		return nil, fmt.Errorf("%v: no users configured", path)
+	}
+	seen := map[string]bool{}
+	users := make([]user, 0, len(cfg.Users))
+	for i, u := range cfg.Users {
+		if u.Name == "" {
+			return nil, fmt.Errorf("%v: user %d has no name", path, i)
+		}
+		if seen[u.Name] {
+			return nil, fmt.Errorf("%v: duplicate user %q", path, u.Name)
+		}
+		seen[u.Name] = true
+		token := u.Token
+		if u.TokenEnv != "" {
+			token = os.Getenv(u.TokenEnv)
+		}
+		if token == "" {
+			return nil, fmt.Errorf("%v: user %q has no token", path, u.Name)
+		}
+		httpClient := &http.Client{Transport: bearerTransport{token: token}, Timeout: 30 * time.Second}
+		users = append(users, user{
+			name:   u.Name,
+			client: whoop.NewClient(httpClient).WithLogger(logger.With("user", u.Name)),
+		})
+	}
+	return users, nil
+}
+
+// bearerTransport authenticates requests with an OAuth2 access token.
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func main() {
+	configPath := flag.String("config", "", "path of the users configuration file")
+	listen := flag.String("listen", ":9876", "address to serve metrics on")
+	interval := flag.Duration("interval", 15*time.Minute, "interval between polls of the API")
+	debug := flag.Bool("debug", false, "log API requests")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	if *configPath == "" {
+		fmt.Fprintln(os.Stderr, "whoop-exporter: -config is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	users, err := loadUsers(*configPath, logger)
+	if err != nil {
+		logger.Error("loading configuration", "error", err)
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	e := newExporter(users, logger)
+	go e.run(ctx, *interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	srv := &http.Server{Addr: *listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+	logger.Info("serving metrics", "addr", *listen, "users", len(users))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("serving metrics", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/export"
+)
+
+// pageSize is the number of records requested per page, the maximum
+// allowed by the API.
+const pageSize = 25
+
+// now returns the current time. It is replaced in tests.
+var now = time.Now
+
+// resource describes a collection of records of type T.
+type resource[T any] struct {
+	list  func(ctx context.Context, c *whoop.Client, p *whoop.RequestParams) ([]T, *string, error)
+	get   func(ctx context.Context, c *whoop.Client, id int) (*T, error)
+	csv   func(w io.Writer, opts export.CSVOptions) (*export.CSVWriter[T], error)
+	table []string // Default columns of table output.
+}
+
+var cycles = resource[whoop.Cycle]{
+	list: func(ctx context.Context, c *whoop.Client, p *whoop.RequestParams) ([]whoop.Cycle, *string, error) {
+		resp, err := c.Cycle.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	},
+	get: func(ctx context.Context, c *whoop.Client, id int) (*whoop.Cycle, error) {
+		return c.Cycle.GetOne(ctx, id)
+	},
+	csv:   export.NewCycleWriter,
+	table: []string{"id", "start", "end", "score_state", "score_strain", "score_kilojoule", "score_average_heart_rate", "score_max_heart_rate"},
+}
+
+var recoveries = resource[whoop.Recovery]{
+	list: func(ctx context.Context, c *whoop.Client, p *whoop.RequestParams) ([]whoop.Recovery, *string, error) {
+		resp, err := c.Recovery.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	},
+	get: func(ctx context.Context, c *whoop.Client, id int) (*whoop.Recovery, error) {
+		return c.Recovery.GetOneByCycleId(ctx, id)
+	},
+	csv:   export.NewRecoveryWriter,
+	table: []string{"cycle_id", "created_at", "score_state", "score_recovery_score", "score_resting_heart_rate", "score_hrv_rmssd_milli", "score_spo2_percentage", "score_skin_temp_celsius"},
+}
+
+var sleeps = resource[whoop.Sleep]{
+	list: func(ctx context.Context, c *whoop.Client, p *whoop.RequestParams) ([]whoop.Sleep, *string, error) {
+		resp, err := c.Sleep.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	},
+	get: func(ctx context.Context, c *whoop.Client, id int) (*whoop.Sleep, error) {
+		return c.Sleep.GetOne(ctx, id)
+	},
+	csv:   export.NewSleepWriter,
+	table: []string{"id", "start", "end", "nap", "score_state", "score_sleep_performance_percentage", "score_sleep_efficiency_percentage", "score_respiratory_rate"},
+}
+
+var workouts = resource[whoop.Workout]{
+	list: func(ctx context.Context, c *whoop.Client, p *whoop.RequestParams) ([]whoop.Workout, *string, error) {
+		resp, err := c.Workout.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	},
+	get: func(ctx context.Context, c *whoop.Client, id int) (*whoop.Workout, error) {
+		return c.Workout.GetOne(ctx, id)
+	},
+	csv:   export.NewWorkoutWriter,
+	table: []string{"id", "start", "end", "sport_name", "score_state", "score_strain", "score_average_heart_rate", "score_max_heart_rate"},
+}
+
+// commonFlags are the flags accepted by every command.
+type commonFlags struct {
+	format  string
+	config  string
+	columns string
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var f commonFlags
+	fs.StringVar(&f.format, "format", "table", "output format: table, json or csv")
+	fs.StringVar(&f.config, "config", defaultConfigPath(), "configuration file")
+	fs.StringVar(&f.columns, "columns", "", "comma-separated columns of table and CSV output")
+	return fs, &f
+}
+
+// parseArgs parses args with fs, allowing flags after positional
+// arguments, and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func (f *commonFlags) check() error {
+	switch f.format {
+	case "table", "json", "csv":
+		return nil
+	}
+	return fmt.Errorf("%w: unknown format %q", errUsage, f.format)
+}
+
+func runRecords[T any](ctx context.Context, r resource[T], args []string, out io.Writer, getenv func(string) string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing subcommand list or get", errUsage)
+	}
+	sub, args := args[0], args[1:]
+	fs, f := newFlagSet(sub)
+	var since, until string
+	var limit int
+	if sub == "list" {
+		fs.StringVar(&since, "since", "", "start of the period")
+		fs.StringVar(&until, "until", "", "end of the period")
+		fs.IntVar(&limit, "limit", pageSize, "maximum number of records, 0 for all")
+	}
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := f.check(); err != nil {
+		return err
+	}
+
+	var records []T
+	switch sub {
+	case "list":
+		if len(positional) != 0 {
+			return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+		}
+		var params whoop.RequestParams
+		if params.Start, err = parseTime(since, now()); err != nil {
+			return fmt.Errorf("%w: --since: %v", errUsage, err)
+		}
+		if params.End, err = parseTime(until, now()); err != nil {
+			return fmt.Errorf("%w: --until: %v", errUsage, err)
+		}
+		if limit < 0 {
+			return fmt.Errorf("%w: --limit must not be negative", errUsage)
+		}
+		client, err := newClient(f.config, getenv)
+		if err != nil {
+			return err
+		}
+		if records, err = listAll(ctx, client, r, params, limit); err != nil {
+			return err
+		}
+	case "get":
+		if len(positional) != 1 {
+			return fmt.Errorf("%w: get takes a single id", errUsage)
+		}
+		id, err := strconv.Atoi(positional[0])
+		if err != nil {
+			return fmt.Errorf("%w: invalid id %q", errUsage, positional[0])
+		}
+		client, err := newClient(f.config, getenv)
+		if err != nil {
+			return err
+		}
+		record, err := r.get(ctx, client, id)
+		if err != nil {
+			return err
+		}
+		if f.format == "json" {
+			return writeJSON(out, record)
+		}
+		records = []T{*record}
+	default:
+		return fmt.Errorf("%w: unknown subcommand %q", errUsage, sub)
+	}
+
+	switch f.format {
+	case "json":
+		if records == nil {
+			records = []T{}
+		}
+		return writeJSON(out, records)
+	case "csv":
+		return writeCSV(out, r.csv, splitColumns(f.columns), records)
+	default:
+		columns := splitColumns(f.columns)
+		if columns == nil {
+			columns = r.table
+		}
+		return writeTable(out, r.csv, columns, records)
+	}
+}
+
+// listAll fetches the pages of records matching params until limit
+// records have been fetched, or every record if limit is 0.
+func listAll[T any](ctx context.Context, client *whoop.Client, r resource[T], params whoop.RequestParams, limit int) ([]T, error) {
+	var records []T
+	for {
+		params.Limit = pageSize
+		if limit > 0 && limit-len(records) < pageSize {
+			params.Limit = limit - len(records)
+		}
+		page, next, err := r.list(ctx, client, &params)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, page...)
+		if limit > 0 && len(records) >= limit {
+			return records[:limit], nil
+		}
+		if next == nil || *next == "" || len(page) == 0 {
+			return records, nil
+		}
+		params.NextToken = *next
+	}
+}
+
+func runProfile(ctx context.Context, args []string, out io.Writer, getenv func(string) string) error {
+	fs, f := newFlagSet("profile")
+	if err := parseFlags(fs, f, args); err != nil {
+		return err
+	}
+	client, err := newClient(f.config, getenv)
+	if err != nil {
+		return err
+	}
+	p, err := client.User.GetProfile(ctx)
+	if err != nil {
+		return err
+	}
+	if f.format == "json" {
+		return writeJSON(out, p)
+	}
+	return writeFields(out, f.format, []field{
+		{"user_id", strconv.Itoa(p.ID)},
+		{"email", deref(p.Email)},
+		{"first_name", deref(p.FirstName)},
+		{"last_name", deref(p.LastName)},
+	})
+}
+
+func runBody(ctx context.Context, args []string, out io.Writer, getenv func(string) string) error {
+	fs, f := newFlagSet("body")
+	if err := parseFlags(fs, f, args); err != nil {
+		return err
+	}
+	client, err := newClient(f.config, getenv)
+	if err != nil {
+		return err
+	}
+	b, err := client.User.GetBodyMeasurement(ctx)
+	if err != nil {
+		return err
+	}
+	if f.format == "json" {
+		return writeJSON(out, b)
+	}
+	return writeFields(out, f.format, []field{
+		{"height_meter", strconv.FormatFloat(b.HeightMeter, 'f', -1, 64)},
+		{"weight_kilogram", strconv.FormatFloat(b.WeightKilogram, 'f', -1, 64)},
+		{"max_heart_rate", strconv.Itoa(b.MaxHeartRate)},
+	})
+}
+
+// parseFlags parses the flags of a command without arguments.
+func parseFlags(fs *flag.FlagSet, f *commonFlags, args []string) error {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 {
+		return fmt.Errorf("%w: unexpected argument %q", errUsage, positional[0])
+	}
+	return f.check()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func splitColumns(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// parseTime parses s as "now", a duration before now such as 12h, 7d or
+// 2w, a date, or an RFC 3339 time. It returns the zero time for an empty s.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	switch {
+	case s == "":
+		return time.Time{}, nil
+	case s == "now":
+		return now, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+	if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n >= 0 {
+		switch s[len(s)-1] {
+		case 'd':
+			return now.AddDate(0, 0, -n), nil
+		case 'w':
+			return now.AddDate(0, 0, -7*n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q", s)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func setup(t *testing.T, handler http.HandlerFunc) func() {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("expected bearer token, got %q", got)
+		}
+		handler(w, r)
+	}))
+	apiURL, _ = url.Parse(srv.URL)
+	return func() {
+		srv.Close()
+		apiURL = nil
+	}
+}
+
+func getenv(key string) string {
+	if key == tokenEnv {
+		return "secret"
+	}
+	return ""
+}
+
+func TestRun_list(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 11, 28, 0, 0, 0, 0, time.UTC) }
+	var limits []string
+	teardown := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got, want := q.Get("start"), "2022-11-21T00:00:00Z"; got != want {
+			t.Errorf("cycles list: expected start %v, got %v", want, got)
+		}
+		limits = append(limits, q.Get("limit"))
+		if q.Get("nextToken") == "" {
+			records := make([]string, 25)
+			for i := range records {
+				records[i] = fmt.Sprintf(`{"id": %d, "start": "2022-11-27T08:00:00Z", "timezone_offset": "-08:00", "score_state": "SCORED", "score": {"strain": 4.5}}`, i+1)
+			}
+			fmt.Fprintf(w, `{"records": [%v], "next_token": "page2"}`, strings.Join(records, ","))
+			return
+		}
+		fmt.Fprint(w, `{"records": [{"id": 26, "score_state": "PENDING_SCORE"}, {"id": 27}], "next_token": "page3"}`)
+	})
+	defer teardown()
+
+	var out bytes.Buffer
+	err := run(context.Background(), []string{"cycles", "list", "--since", "7d", "--limit", "26", "--format", "csv", "--columns", "id,start,score_strain"}, &out, getenv)
+	if err != nil {
+		t.Fatalf("run(): expected nil error, got %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 27 {
+		t.Fatalf("run(): expected header and 26 rows, got %v lines", len(lines))
+	}
+	if lines[0] != "id,start,score_strain" || lines[1] != "1,2022-11-27T00:00:00-08:00,4.5" || lines[26] != "26,," {
+		t.Errorf("run(): got lines %q, %q, %q", lines[0], lines[1], lines[26])
+	}
+	if got := strings.Join(limits, ","); got != "25,1" {
+		t.Errorf("run(): expected page limits 25,1, got %v", got)
+	}
+}
+
+func TestRun_get(t *testing.T) {
+	teardown := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/activity/workout/7") {
+			t.Errorf("workouts get: unexpected path %v", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"id": 7, "sport_name": "running", "score_state": "SCORED", "score": {"strain": 12.5}}`)
+	})
+	defer teardown()
+
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"workouts", "get", "7", "--format", "json"}, &out, getenv); err != nil {
+		t.Fatalf("run(): expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), `"sport_name": "Running"`) {
+		t.Errorf("run(): got %v", out.String())
+	}
+
+	out.Reset()
+	if err := run(context.Background(), []string{"workouts", "get", "7"}, &out, getenv); err != nil {
+		t.Fatalf("run(): expected nil error, got %v", err)
+	}
+	if want := "ID  START  END  SPORT_NAME  SCORE_STATE  STRAIN  AVERAGE_HEART_RATE  MAX_HEART_RATE\n7               Running     SCORED       12.5    0                   0\n"; out.String() != want {
+		t.Errorf("run(): got\n%q\nwant\n%q", out.String(), want)
+	}
+}
+
+func TestRun_profile(t *testing.T) {
+	teardown := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"user_id": 10, "email": "jo@example.com", "first_name": "Jo", "last_name": "Doe"}`)
+	})
+	defer teardown()
+
+	var out bytes.Buffer
+	if err := run(context.Background(), []string{"profile", "--format", "csv"}, &out, getenv); err != nil {
+		t.Fatalf("run(): expected nil error, got %v", err)
+	}
+	if want := "user_id,email,first_name,last_name\n10,jo@example.com,Jo,Doe\n"; out.String() != want {
+		t.Errorf("run(): got %q, want %q", out.String(), want)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"", time.Time{}},
+		{"now", now},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"36h", now.Add(-36 * time.Hour)},
+		{"2022-11-01", time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"2022-11-01T10:00:00Z", time.Date(2022, 11, 1, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, test := range tests {
+		got, err := parseTime(test.in, now)
+		if err != nil || !got.Equal(test.want) {
+			t.Errorf("parseTime(%q): got %v, %v, want %v", test.in, got, err, test.want)
+		}
+	}
+	if _, err := parseTime("-3d", now); err == nil {
+		t.Error("parseTime(-3d): expected error, got nil")
+	}
+}
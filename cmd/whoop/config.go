@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// tokenEnv is the environment variable holding the access token.
+const tokenEnv = "WHOOP_TOKEN"
+
+// apiURL is the base URL of API requests. It is replaced in tests, and nil
+// uses the WHOOP API.
+var apiURL *url.URL
+
+// config is the configuration file of the command.
+type config struct {
+	Token string `json:"token"` // OAuth2 access token.
+}
+
+// defaultConfigPath returns the path of the configuration file in the
+// user's configuration directory.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "whoop", "config.json")
+}
+
+// loadToken returns the access token from the environment or else from
+// the configuration file at path.
+func loadToken(path string, getenv func(string) string) (string, error) {
+	if token := getenv(tokenEnv); token != "" {
+		return token, nil
+	}
+	if path == "" {
+		return "", fmt.Errorf("no access token: set %v or create a configuration file", tokenEnv)
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("no access token: set %v or create %v", tokenEnv, path)
+	}
+	if err != nil {
+		return "", err
+	}
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return "", fmt.Errorf("%v: %w", path, err)
+	}
+	if cfg.Token == "" {
+		return "", fmt.Errorf("%v: no token", path)
+	}
+	return cfg.Token, nil
+}
+
+// newClient returns a client authenticated with the access token found by
+// loadToken.
+func newClient(path string, getenv func(string) string) (*whoop.Client, error) {
+	token, err := loadToken(path, getenv)
+	if err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Transport: bearerTransport{token: token, base: http.DefaultTransport}, Timeout: time.Minute}
+	client := whoop.NewClient(httpClient)
+	if apiURL != nil {
+		client.WithBaseURL(apiURL)
+	}
+	return client, nil
+}
+
+// bearerTransport authenticates requests with an OAuth2 access token.
+type bearerTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(r)
+}
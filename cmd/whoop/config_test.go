@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadToken(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	noenv := func(string) string { return "" }
+
+	if _, err := loadToken(path, noenv); err == nil {
+		t.Error("loadToken(): expected error for missing configuration, got nil")
+	}
+
+	os.WriteFile(path, []byte(`{"token": "from-file"}`), 0o600)
+	if got, err := loadToken(path, noenv); err != nil || got != "from-file" {
+		t.Errorf("loadToken(): got %q, %v, want from-file", got, err)
+	}
+	if got, _ := loadToken(path, getenv); got != "secret" {
+		t.Errorf("loadToken(): expected environment to take precedence, got %q", got)
+	}
+
+	os.WriteFile(path, []byte(`{}`), 0o600)
+	if _, err := loadToken(path, noenv); err == nil {
+		t.Error("loadToken(): expected error for empty token, got nil")
+	}
+}
@@ -0,0 +1,89 @@
+// Command whoop is a command-line client of the WHOOP API.
+//
+// Usage:
+//
+//	whoop profile
+//	whoop body
+//	whoop cycles|sleeps|recoveries|workouts list [--since 7d] [--until now] [--limit 25]
+//	whoop cycles|sleeps|recoveries|workouts get <id>
+//
+// Every command accepts --format table|json|csv. Lists are paginated
+// automatically until --limit records have been fetched; --limit 0 fetches
+// every record in the period. --since and --until accept a duration before
+// now such as 12h, 7d or 2w, a date such as 2022-11-27, an RFC 3339 time,
+// or "now". The id of a recovery is the id of its cycle.
+//
+// The OAuth2 access token is read from the WHOOP_TOKEN environment
+// variable, or else from the "token" field of the JSON configuration file
+// given by --config, which defaults to whoop/config.json in the user's
+// configuration directory.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+)
+
+const usage = `Usage:
+  whoop profile
+  whoop body
+  whoop cycles|sleeps|recoveries|workouts list [--since 7d] [--until now] [--limit 25]
+  whoop cycles|sleeps|recoveries|workouts get <id>
+
+Flags:
+  --format table|json|csv  output format (default table)
+  --config path            configuration file with the access token
+  --columns a,b            columns of table and CSV output of records
+`
+
+// errUsage is returned for invalid command lines.
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	err := run(ctx, os.Args[1:], os.Stdout, os.Getenv)
+	switch {
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "whoop:", err)
+		}
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "whoop:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command line args, writing its output to out. getenv is
+// used to look up environment variables.
+func run(ctx context.Context, args []string, out io.Writer, getenv func(string) string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "profile":
+		return runProfile(ctx, args, out, getenv)
+	case "body":
+		return runBody(ctx, args, out, getenv)
+	case "cycles":
+		return runRecords(ctx, cycles, args, out, getenv)
+	case "sleeps":
+		return runRecords(ctx, sleeps, args, out, getenv)
+	case "recoveries":
+		return runRecords(ctx, recoveries, args, out, getenv)
+	case "workouts":
+		return runRecords(ctx, workouts, args, out, getenv)
+	case "help", "-h", "-help", "--help":
+		return flag.ErrHelp
+	default:
+		return fmt.Errorf("%w: unknown command %q", errUsage, cmd)
+	}
+}
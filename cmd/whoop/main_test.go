@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"testing"
+)
+
+func TestRun_usage(t *testing.T) {
+	tests := []struct {
+		args []string
+		want error
+	}{
+		{nil, errUsage},
+		{[]string{"nope"}, errUsage},
+		{[]string{"help"}, flag.ErrHelp},
+		{[]string{"cycles"}, errUsage},
+		{[]string{"cycles", "delete"}, errUsage},
+		{[]string{"cycles", "get"}, errUsage},
+		{[]string{"cycles", "get", "abc"}, errUsage},
+		{[]string{"cycles", "list", "--format", "xml"}, errUsage},
+		{[]string{"cycles", "list", "--since", "yesterday"}, errUsage},
+		{[]string{"profile", "extra"}, errUsage},
+	}
+	for _, test := range tests {
+		err := run(context.Background(), test.args, &bytes.Buffer{}, func(string) string { return "token" })
+		if !errors.Is(err, test.want) {
+			t.Errorf("run(%q): got error %v, want %v", test.args, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ferueda/go-whoop/whoop/export"
+)
+
+// field is a named value of a single object such as the user profile.
+type field struct {
+	name, value string
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeCSV writes records as CSV with the given columns, or every column
+// if columns is nil.
+func writeCSV[T any](w io.Writer, newWriter func(io.Writer, export.CSVOptions) (*export.CSVWriter[T], error), columns []string, records []T) error {
+	cw, err := newWriter(w, export.CSVOptions{Columns: columns})
+	if err != nil {
+		return err
+	}
+	if err := cw.Write(records...); err != nil {
+		return err
+	}
+	return cw.Flush()
+}
+
+// writeTable writes records as an aligned table with the given columns.
+func writeTable[T any](w io.Writer, newWriter func(io.Writer, export.CSVOptions) (*export.CSVWriter[T], error), columns []string, records []T) error {
+	var buf bytes.Buffer
+	if err := writeCSV(&buf, newWriter, columns, records); err != nil {
+		return err
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		return err
+	}
+	for i, name := range rows[0] {
+		if name != "score_state" {
+			name = strings.TrimPrefix(name, "score_")
+		}
+		rows[0][i] = strings.ToUpper(name)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		io.WriteString(tw, strings.Join(row, "\t")+"\n")
+	}
+	return tw.Flush()
+}
+
+// writeFields writes fields as a two-column table, or as a CSV header and
+// row.
+func writeFields(w io.Writer, format string, fields []field) error {
+	if format == "csv" {
+		cw := csv.NewWriter(w)
+		names := make([]string, len(fields))
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			names[i], values[i] = f.name, f.value
+		}
+		cw.Write(names)
+		cw.Write(values)
+		cw.Flush()
+		return cw.Error()
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, f := range fields {
+		io.WriteString(tw, strings.ToUpper(f.name)+"\t"+f.value+"\n")
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteFields(t *testing.T) {
+	fields := []field{{"height_meter", "1.8"}, {"max_heart_rate", "190"}}
+	var buf bytes.Buffer
+	writeFields(&buf, "table", fields)
+	if want := "HEIGHT_METER    1.8\nMAX_HEART_RATE  190\n"; buf.String() != want {
+		t.Errorf("writeFields(): got %q, want %q", buf.String(), want)
+	}
+}
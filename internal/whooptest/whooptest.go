@@ -0,0 +1,30 @@
+// Package whooptest provides helpers for the tests of packages built on
+// the WHOOP API client.
+package whooptest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// Ptr returns a pointer to v.
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// NewClient returns a client whose requests are served by handler. The
+// test server is closed when the test ends.
+func NewClient(t testing.TB, handler http.Handler) *whoop.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("url.Parse(%v): %v", server.URL, err)
+	}
+	return whoop.NewClient(nil).WithBaseURL(u)
+}
@@ -0,0 +1,189 @@
+// Package analytics computes trends and indicators from WHOOP records.
+//
+// Records are grouped by day. Days are calendar dates in the member's
+// local time, represented as midnight UTC. A cycle or sleep belongs to the
+// day most of its waking hours fall on, which is the local date twelve
+// hours after it starts, so that a cycle starting at 11pm belongs to the
+// next day. A recovery belongs to the day of its cycle.
+//
+// Days without data are explicitly represented as missing rather than
+// skipped, so that windows always span a fixed number of calendar days.
+package analytics
+
+import (
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// Metric is a daily metric.
+type Metric string
+
+// Metrics computed from recoveries, sleeps and cycles.
+const (
+	RecoveryScore    Metric = "recovery_score"     // Recovery.Score.RecoveryScore.
+	HRV              Metric = "hrv_rmssd_milli"    // Recovery.Score.HrvRmssdMilli.
+	RestingHeartRate Metric = "resting_heart_rate" // Recovery.Score.RestingHeartRate.
+	RespiratoryRate  Metric = "respiratory_rate"   // Sleep.Score.RespiratoryRate, excluding naps.
+	SkinTemp         Metric = "skin_temp_celsius"  // Recovery.Score.SkinTempCelsius.
+	Strain           Metric = "strain"             // Cycle.Score.Strain of completed cycles.
+)
+
+// Metrics lists every Metric.
+var Metrics = []Metric{RecoveryScore, HRV, RestingHeartRate, RespiratoryRate, SkinTemp, Strain}
+
+// Day is the value of a metric on a day.
+type Day struct {
+	Date    time.Time // Local calendar date, as midnight UTC.
+	Value   float64   // Value of the metric. Zero if the day is missing.
+	Present bool      // False if there is no data for the day.
+}
+
+// Series is the daily series of a metric over consecutive days. Days
+// without data are present in the series with Present set to false. If
+// several records fall on the same day, their values are averaged.
+type Series struct {
+	Metric Metric
+	Days   []Day
+}
+
+// Date returns the local calendar date of t in the time zone offset, such
+// as "-08:00", as midnight UTC. A nil or invalid offset is treated as UTC.
+func Date(t time.Time, offset *string) time.Time {
+	t = t.In(whoop.ParseOffset(offset))
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CycleDate returns the day of c. It returns false if c has no start.
+func CycleDate(c *whoop.Cycle) (time.Time, bool) {
+	if c.Start == nil {
+		return time.Time{}, false
+	}
+	return Date(c.Start.Add(12*time.Hour), c.TimezoneOffset), true
+}
+
+// SleepDate returns the day of s. It returns false if s has no start.
+func SleepDate(s *whoop.Sleep) (time.Time, bool) {
+	if s.Start == nil {
+		return time.Time{}, false
+	}
+	return Date(s.Start.Add(12*time.Hour), s.TimezoneOffset), true
+}
+
+// RecoveryDate returns the day of r, which is the day of its cycle in
+// cycles, or else the UTC date it was created on. It returns false if
+// neither is known.
+func RecoveryDate(r *whoop.Recovery, cycles map[int]*whoop.Cycle) (time.Time, bool) {
+	if c, ok := cycles[r.CycleID]; ok {
+		if d, ok := CycleDate(c); ok {
+			return d, true
+		}
+	}
+	if r.CreatedAt == nil {
+		return time.Time{}, false
+	}
+	return Date(*r.CreatedAt, nil), true
+}
+
+// DailySeries returns the daily series of every metric, from the first to
+// the last day with data across all metrics. Unscored records, recoveries
+// of calibrating members, naps and cycles in progress are skipped, and a
+// skin temperature of zero is treated as missing.
+func DailySeries(cycles []whoop.Cycle, recoveries []whoop.Recovery, sleeps []whoop.Sleep) map[Metric]Series {
+	type acc struct{ sum, n float64 }
+	values := map[Metric]map[time.Time]*acc{}
+	for _, m := range Metrics {
+		values[m] = map[time.Time]*acc{}
+	}
+	var first, last time.Time
+	add := func(m Metric, d time.Time, v float64) {
+		a, ok := values[m][d]
+		if !ok {
+			a = &acc{}
+			values[m][d] = a
+		}
+		a.sum += v
+		a.n++
+		if first.IsZero() || d.Before(first) {
+			first = d
+		}
+		if d.After(last) {
+			last = d
+		}
+	}
+
+	byID := make(map[int]*whoop.Cycle, len(cycles))
+	for i := range cycles {
+		c := &cycles[i]
+		byID[c.ID] = c
+		if !c.Scored() || c.End == nil {
+			continue
+		}
+		if d, ok := CycleDate(c); ok {
+			add(Strain, d, c.Score.Strain)
+		}
+	}
+	for i := range recoveries {
+		r := &recoveries[i]
+		if !r.Scored() || r.Score.UserCalibrating {
+			continue
+		}
+		d, ok := RecoveryDate(r, byID)
+		if !ok {
+			continue
+		}
+		add(RecoveryScore, d, r.Score.RecoveryScore)
+		add(HRV, d, r.Score.HrvRmssdMilli)
+		add(RestingHeartRate, d, r.Score.RestingHeartRate)
+		if r.Score.SkinTempCelsius != 0 {
+			add(SkinTemp, d, r.Score.SkinTempCelsius)
+		}
+	}
+	for i := range sleeps {
+		s := &sleeps[i]
+		if !s.Scored() || s.Nap {
+			continue
+		}
+		if d, ok := SleepDate(s); ok {
+			add(RespiratoryRate, d, s.Score.RespiratoryRate)
+		}
+	}
+
+	series := make(map[Metric]Series, len(Metrics))
+	for _, m := range Metrics {
+		s := Series{Metric: m}
+		if !first.IsZero() {
+			for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+				day := Day{Date: d}
+				if a, ok := values[m][d]; ok {
+					day.Value, day.Present = a.sum/a.n, true
+				}
+				s.Days = append(s.Days, day)
+			}
+		}
+		series[m] = s
+	}
+	return series
+}
+
+// At returns the value of s on date. It returns false if date is missing
+// or outside of s.
+func (s Series) At(date time.Time) (float64, bool) {
+	i := s.index(date)
+	if i < 0 || !s.Days[i].Present {
+		return 0, false
+	}
+	return s.Days[i].Value, true
+}
+
+// index returns the index of date in s.Days, or -1.
+func (s Series) index(date time.Time) int {
+	if len(s.Days) == 0 {
+		return -1
+	}
+	i := int(date.Sub(s.Days[0].Date).Hours() / 24)
+	if i < 0 || i >= len(s.Days) || !s.Days[i].Date.Equal(date) {
+		return -1
+	}
+	return i
+}
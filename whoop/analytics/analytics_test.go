@@ -0,0 +1,102 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func day(d int) time.Time {
+	return time.Date(2022, 11, d, 0, 0, 0, 0, time.UTC)
+}
+
+// cycle returns a completed scored cycle starting at 11pm local time the
+// evening before day d.
+func cycle(id, d int, strain float64) whoop.Cycle {
+	start := time.Date(2022, 11, d, 7, 0, 0, 0, time.UTC) // 11pm at -08:00.
+	end := start.Add(24 * time.Hour)
+	c := whoop.Cycle{ID: id, Start: &start, End: &end, TimezoneOffset: whooptest.Ptr("-08:00"), ScoreState: whooptest.Ptr("SCORED")}
+	c.Score.Strain = strain
+	return c
+}
+
+func recovery(cycleID int, score float64) whoop.Recovery {
+	r := whoop.Recovery{CycleID: cycleID, ScoreState: whooptest.Ptr("SCORED")}
+	r.Score.RecoveryScore = score
+	r.Score.HrvRmssdMilli = score
+	r.Score.RestingHeartRate = 50
+	return r
+}
+
+func TestDate(t *testing.T) {
+	ts := time.Date(2022, 11, 27, 6, 0, 0, 0, time.UTC)
+	if got := Date(ts, whooptest.Ptr("-08:00")); !got.Equal(day(26)) {
+		t.Errorf("Date(): got %v, want %v", got, day(26))
+	}
+	if got := Date(ts, nil); !got.Equal(day(27)) {
+		t.Errorf("Date(): got %v, want %v", got, day(27))
+	}
+	c := cycle(1, 26, 0)
+	if got, _ := CycleDate(&c); !got.Equal(day(26)) {
+		t.Errorf("CycleDate(): got %v, want %v", got, day(26))
+	}
+}
+
+func TestDailySeries(t *testing.T) {
+	inProgress := cycle(4, 24, 3)
+	inProgress.End = nil
+	calibrating := recovery(2, 90)
+	calibrating.Score.UserCalibrating = true
+	pending := recovery(3, 10)
+	pending.ScoreState = whooptest.Ptr("PENDING_SCORE")
+
+	sleepStart := time.Date(2022, 11, 22, 7, 0, 0, 0, time.UTC)
+	napStart := time.Date(2022, 11, 22, 22, 0, 0, 0, time.UTC)
+	sleep := whoop.Sleep{Start: &sleepStart, TimezoneOffset: whooptest.Ptr("-08:00"), ScoreState: whooptest.Ptr("SCORED")}
+	sleep.Score.RespiratoryRate = 15
+	nap := whoop.Sleep{Start: &napStart, Nap: true, ScoreState: whooptest.Ptr("SCORED")}
+	nap.Score.RespiratoryRate = 20
+
+	series := DailySeries(
+		[]whoop.Cycle{cycle(1, 20, 10), cycle(2, 21, 12), cycle(3, 23, 8), inProgress},
+		[]whoop.Recovery{recovery(1, 60), calibrating, pending},
+		[]whoop.Sleep{sleep, nap},
+	)
+
+	strain := series[Strain]
+	if len(strain.Days) != 4 || !strain.Days[0].Date.Equal(day(20)) || !strain.Days[3].Date.Equal(day(23)) {
+		t.Fatalf("DailySeries(): expected strain from the 20th to the 23rd, got %+v", strain.Days)
+	}
+	for i, want := range []Day{
+		{day(20), 10, true}, {day(21), 12, true}, {day(22), 0, false}, {day(23), 8, true},
+	} {
+		if strain.Days[i] != want {
+			t.Errorf("DailySeries(): strain day %v: got %+v, want %+v", i, strain.Days[i], want)
+		}
+	}
+
+	if v, ok := series[RecoveryScore].At(day(20)); !ok || v != 60 {
+		t.Errorf("DailySeries(): expected recovery 60 on the 20th, got %v, %v", v, ok)
+	}
+	if _, ok := series[RecoveryScore].At(day(21)); ok {
+		t.Error("DailySeries(): expected calibrating recovery to be skipped")
+	}
+	if _, ok := series[SkinTemp].At(day(20)); ok {
+		t.Error("DailySeries(): expected zero skin temperature to be missing")
+	}
+	if v, ok := series[RespiratoryRate].At(day(22)); !ok || v != 15 {
+		t.Errorf("DailySeries(): expected respiratory rate 15 on the 22nd without the nap, got %v, %v", v, ok)
+	}
+	if _, ok := series[Strain].At(day(30)); ok {
+		t.Error("Series.At(): expected day outside of series to be missing")
+	}
+}
+
+func TestDailySeries_empty(t *testing.T) {
+	series := DailySeries(nil, nil, nil)
+	if len(series) != len(Metrics) || len(series[HRV].Days) != 0 {
+		t.Errorf("DailySeries(): expected empty series of every metric, got %v", series)
+	}
+}
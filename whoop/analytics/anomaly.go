@@ -0,0 +1,203 @@
+package analytics
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// AnomalyOptions configures anomaly detection. The zero value flags
+// deviations of 2 standard deviations from a 30 day baseline.
+type AnomalyOptions struct {
+	// Threshold is the number of standard deviations from the baseline
+	// beyond which a signal is anomalous. Defaults to 2.
+	Threshold float64
+
+	// Window is the length of the baseline, in days. Defaults to 30.
+	Window int
+
+	// MinCoverage is the minimum fraction of the days of the baseline
+	// with data. Defaults to 0.5. See Options.
+	MinCoverage float64
+
+	// MinSignals is the number of anomalous signals required to raise an
+	// alert. Defaults to 1.
+	MinSignals int
+}
+
+func (o AnomalyOptions) withDefaults() AnomalyOptions {
+	if o.Threshold <= 0 {
+		o.Threshold = 2
+	}
+	if o.Window <= 0 {
+		o.Window = 30
+	}
+	if o.MinSignals <= 0 {
+		o.MinSignals = 1
+	}
+	return o
+}
+
+// Signal is a metric which deviates from the member's baseline.
+type Signal struct {
+	Metric   Metric
+	Value    float64 // Value on the day.
+	Baseline float64 // Mean of the baseline.
+	StdDev   float64 // Standard deviation of the baseline.
+	ZScore   float64 // Standard score of Value against the baseline.
+}
+
+// Alert is a day on which one or more signals deviate from the member's
+// baseline in the direction associated with illness.
+type Alert struct {
+	Date    time.Time
+	Signals []Signal
+
+	// Score is the sum of the absolute z-scores of the signals. Higher
+	// scores mean more signals or larger deviations.
+	Score float64
+
+	// Explanation describes the signals in plain English.
+	Explanation string
+}
+
+// anomaly describes how a metric deviates when a member may be getting
+// sick.
+type anomaly struct {
+	metric    Metric
+	name      string
+	unit      string
+	direction int // -1 for drops, 1 for rises, 0 for either.
+}
+
+var anomalies = []anomaly{
+	{HRV, "HRV", "ms", -1},
+	{RestingHeartRate, "resting heart rate", "bpm", 1},
+	{RespiratoryRate, "respiratory rate", "rpm", 1},
+	{SkinTemp, "skin temperature", "°C", 0},
+}
+
+// DetectAnomalies returns the days on which HRV drops, resting heart rate
+// rises, respiratory rate rises or skin temperature deviates beyond the
+// threshold of opts from the member's baseline, in chronological order.
+//
+// The baseline of a day is the window of days before it; see Stats.
+// Recoveries of calibrating members, naps and unscored records are
+// skipped. A recovery belongs to the day of its sleep. Detection only
+// depends on its input, so results are reproducible.
+func DetectAnomalies(recoveries []whoop.Recovery, sleeps []whoop.Sleep, opts AnomalyOptions) []Alert {
+	opts = opts.withDefaults()
+	minSamples := Options{MinCoverage: opts.MinCoverage}.minSamples(opts.Window)
+
+	sleepDates := map[int]time.Time{}
+	values := map[Metric]map[time.Time][]float64{}
+	for _, a := range anomalies {
+		values[a.metric] = map[time.Time][]float64{}
+	}
+	var first, last time.Time
+	add := func(m Metric, d time.Time, v float64) {
+		values[m][d] = append(values[m][d], v)
+		if first.IsZero() || d.Before(first) {
+			first = d
+		}
+		if d.After(last) {
+			last = d
+		}
+	}
+	for i := range sleeps {
+		s := &sleeps[i]
+		d, ok := SleepDate(s)
+		if !ok {
+			continue
+		}
+		sleepDates[s.ID] = d
+		if s.Scored() && !s.Nap {
+			add(RespiratoryRate, d, s.Score.RespiratoryRate)
+		}
+	}
+	for i := range recoveries {
+		r := &recoveries[i]
+		if !r.Scored() || r.Score.UserCalibrating {
+			continue
+		}
+		d, ok := sleepDates[r.SleepID]
+		if !ok {
+			if r.CreatedAt == nil {
+				continue
+			}
+			d = Date(*r.CreatedAt, nil)
+		}
+		add(HRV, d, r.Score.HrvRmssdMilli)
+		add(RestingHeartRate, d, r.Score.RestingHeartRate)
+		if r.Score.SkinTempCelsius != 0 {
+			add(SkinTemp, d, r.Score.SkinTempCelsius)
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	stats := map[Metric][]Stats{}
+	for _, a := range anomalies {
+		s := Series{Metric: a.metric}
+		for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+			day := Day{Date: d}
+			if v := values[a.metric][d]; len(v) > 0 {
+				day.Value, _ = meanStdDev(v)
+				day.Present = true
+			}
+			s.Days = append(s.Days, day)
+		}
+		stats[a.metric] = s.Rolling(opts.Window, minSamples)
+	}
+
+	var alerts []Alert
+	for i := range stats[HRV] {
+		var alert Alert
+		var reasons []string
+		for _, a := range anomalies {
+			st := stats[a.metric][i]
+			z, ok := st.ZScore()
+			if !ok || !deviates(z, a.direction, opts.Threshold) {
+				continue
+			}
+			alert.Signals = append(alert.Signals, Signal{Metric: a.metric, Value: st.Value, Baseline: st.Mean, StdDev: st.StdDev, ZScore: z})
+			alert.Score += math.Abs(z)
+			dir := "above"
+			if z < 0 {
+				dir = "below"
+			}
+			reasons = append(reasons, fmt.Sprintf("%v %.1f %v is %.1f SD %v the %d-day baseline of %.1f %v",
+				a.name, st.Value, a.unit, math.Abs(z), dir, opts.Window, st.Mean, a.unit))
+		}
+		if len(alert.Signals) < opts.MinSignals {
+			continue
+		}
+		alert.Date = first.AddDate(0, 0, i)
+		alert.Explanation = capitalize(strings.Join(reasons, "; ")) + "."
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// deviates reports whether z is beyond threshold in direction.
+func deviates(z float64, direction int, threshold float64) bool {
+	switch direction {
+	case -1:
+		return z <= -threshold
+	case 1:
+		return z >= threshold
+	default:
+		return math.Abs(z) >= threshold
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,94 @@
+package analytics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// history returns 20 nights of sleeps and recoveries with a stable
+// baseline, alternating around HRV 60 ms, resting heart rate 50 bpm,
+// respiratory rate 15 rpm and skin temperature 33.5°C.
+func history() ([]whoop.Recovery, []whoop.Sleep) {
+	var recoveries []whoop.Recovery
+	var sleeps []whoop.Sleep
+	for d := 1; d <= 20; d++ {
+		delta := float64(d%2)*2 - 1 // -1 or 1
+		s := night(d, d, 8, 8)
+		s.Score.RespiratoryRate = 15 + delta*0.2
+		r := whoop.Recovery{SleepID: d, ScoreState: whooptest.Ptr("SCORED")}
+		r.Score.HrvRmssdMilli = 60 + delta*2
+		r.Score.RestingHeartRate = 50 + delta
+		r.Score.SkinTempCelsius = 33.5 + delta*0.1
+		sleeps = append(sleeps, s)
+		recoveries = append(recoveries, r)
+	}
+	return recoveries, sleeps
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	recoveries, sleeps := history()
+	// The 19th: HRV drops and resting heart rate rises.
+	recoveries[18].Score.HrvRmssdMilli = 45
+	recoveries[18].Score.RestingHeartRate = 58
+	// The 20th: HRV rises, which is not a warning sign.
+	recoveries[19].Score.HrvRmssdMilli = 80
+
+	alerts := DetectAnomalies(recoveries, sleeps, AnomalyOptions{Window: 14})
+	if len(alerts) != 1 {
+		t.Fatalf("DetectAnomalies(): expected 1 alert, got %+v", alerts)
+	}
+	a := alerts[0]
+	if !a.Date.Equal(day(19)) {
+		t.Errorf("DetectAnomalies(): expected alert on the 19th, got %v", a.Date)
+	}
+	if len(a.Signals) != 2 || a.Signals[0].Metric != HRV || a.Signals[1].Metric != RestingHeartRate {
+		t.Fatalf("DetectAnomalies(): got signals %+v", a.Signals)
+	}
+	if a.Signals[0].ZScore > -2 || a.Signals[1].ZScore < 2 {
+		t.Errorf("DetectAnomalies(): got z-scores %v and %v", a.Signals[0].ZScore, a.Signals[1].ZScore)
+	}
+	if a.Score != -a.Signals[0].ZScore+a.Signals[1].ZScore {
+		t.Errorf("DetectAnomalies(): got score %v", a.Score)
+	}
+	if !strings.HasPrefix(a.Explanation, "HRV 45.0 ms is ") || !strings.Contains(a.Explanation, "; resting heart rate 58.0 bpm is ") || !strings.Contains(a.Explanation, "SD above the 14-day baseline of 50.0 bpm") {
+		t.Errorf("DetectAnomalies(): got explanation %q", a.Explanation)
+	}
+
+	if alerts := DetectAnomalies(recoveries, sleeps, AnomalyOptions{Window: 14, MinSignals: 3}); len(alerts) != 0 {
+		t.Errorf("DetectAnomalies(): expected no alert with 3 signals required, got %+v", alerts)
+	}
+}
+
+func TestDetectAnomalies_skinTemp(t *testing.T) {
+	recoveries, sleeps := history()
+	recoveries[19].Score.SkinTempCelsius = 32.5
+	recoveries[19].ScoreState = whooptest.Ptr("SCORED")
+	calibrating := recoveries[18]
+	calibrating.Score.UserCalibrating = true
+	calibrating.Score.HrvRmssdMilli = 10
+	recoveries[18] = calibrating
+
+	alerts := DetectAnomalies(recoveries, sleeps, AnomalyOptions{})
+	if len(alerts) != 1 || !alerts[0].Date.Equal(day(20)) || alerts[0].Signals[0].Metric != SkinTemp {
+		t.Fatalf("DetectAnomalies(): expected a skin temperature alert on the 20th, got %+v", alerts)
+	}
+	if !strings.Contains(alerts[0].Explanation, "SD below the 30-day baseline") {
+		t.Errorf("DetectAnomalies(): got explanation %q", alerts[0].Explanation)
+	}
+}
+
+func TestDetectAnomalies_deterministic(t *testing.T) {
+	recoveries, sleeps := history()
+	recoveries[15].Score.RestingHeartRate = 60
+	a := DetectAnomalies(recoveries, sleeps, AnomalyOptions{Window: 10})
+	b := DetectAnomalies(recoveries, sleeps, AnomalyOptions{Window: 10})
+	if len(a) == 0 || len(a) != len(b) || a[0].Explanation != b[0].Explanation || a[0].Score != b[0].Score {
+		t.Errorf("DetectAnomalies(): expected identical results, got %+v and %+v", a, b)
+	}
+	if DetectAnomalies(nil, nil, AnomalyOptions{}) != nil {
+		t.Error("DetectAnomalies(): expected no alerts without records")
+	}
+}
@@ -0,0 +1,23 @@
+package analytics
+
+// Band is the color band of a recovery score.
+type Band string
+
+// Recovery bands, as shown in the WHOOP app.
+const (
+	Red    Band = "red"    // Recovery scores below 34%.
+	Yellow Band = "yellow" // Recovery scores from 34% to 66%.
+	Green  Band = "green"  // Recovery scores of 67% and above.
+)
+
+// RecoveryBand returns the band of a recovery score.
+func RecoveryBand(score float64) Band {
+	switch {
+	case score >= 67:
+		return Green
+	case score >= 34:
+		return Yellow
+	default:
+		return Red
+	}
+}
@@ -0,0 +1,23 @@
+package analytics
+
+import "testing"
+
+func TestRecoveryBand(t *testing.T) {
+	tests := []struct {
+		score float64
+		want  Band
+	}{
+		{0, Red},
+		{33, Red},
+		{33.5, Red},
+		{34, Yellow},
+		{66, Yellow},
+		{67, Green},
+		{100, Green},
+	}
+	for _, tt := range tests {
+		if got := RecoveryBand(tt.score); got != tt.want {
+			t.Errorf("RecoveryBand(%v): got %v, want %v", tt.score, got, tt.want)
+		}
+	}
+}
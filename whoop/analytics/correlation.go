@@ -0,0 +1,274 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// Features of a day correlated with the next day's recovery.
+const (
+	FeatureWorkoutEnd       = "workout_end_hour"  // Hours from local midnight at the start of the day to the end of the last workout, 24 or more after midnight. Missing on days without workouts.
+	FeatureStrain           = "strain"            // Strain of the cycle.
+	FeatureSleepHours       = "sleep_hours"       // Time asleep the following night, in hours.
+	FeatureSleepConsistency = "sleep_consistency" // Sleep consistency of the following night, in percent.
+	FeatureNap              = "nap"               // 1 if the member napped during the cycle, 0 otherwise.
+
+	// FeatureSportPrefix prefixes the name of a sport to form a feature
+	// which is 1 if the member did a workout of the sport during the
+	// cycle, 0 otherwise.
+	FeatureSportPrefix = "sport:"
+)
+
+// FeatureDay pairs the features of a cycle with the recovery of the next
+// cycle.
+type FeatureDay struct {
+	Date     time.Time // Day of the cycle.
+	CycleID  int
+	Features map[string]float64 // Features of the day, by name. Missing features are absent.
+
+	RecoveryScore float64 // Recovery score of the next cycle.
+	HRV           float64 // HRV of the next cycle, in milliseconds.
+}
+
+// Estimate is a correlation coefficient with its confidence interval.
+type Estimate struct {
+	R         float64
+	Low, High float64 // Bounds of the confidence interval.
+	Valid     bool    // False if there are fewer than 4 pairs or no variation.
+}
+
+// Correlation is the correlation between a feature and an outcome.
+type Correlation struct {
+	Feature  string
+	Outcome  Metric // RecoveryScore or HRV.
+	N        int    // Number of days with both the feature and the outcome.
+	Pearson  Estimate
+	Spearman Estimate
+}
+
+// CorrelationOptions configures Correlations.
+type CorrelationOptions struct {
+	// Confidence is the level of the confidence intervals. Defaults to 0.95.
+	Confidence float64
+}
+
+// CorrelationReport holds the paired days and the correlations of every
+// feature with the next day's recovery score and HRV.
+type CorrelationReport struct {
+	Days         []FeatureDay
+	Correlations []Correlation // Sorted by feature, then outcome.
+}
+
+// Correlations pairs the features of each completed cycle with the
+// recovery of the next cycle as PairDays does, and computes the Pearson
+// and Spearman correlations of every feature with the recovery score and
+// HRV.
+//
+// Confidence intervals use the Fisher transformation, with the standard
+// error of the Spearman coefficient from Fieller, Hartley and Pearson
+// (1957).
+func Correlations(cycles []whoop.Cycle, recoveries []whoop.Recovery, sleeps []whoop.Sleep, workouts []whoop.Workout, opts CorrelationOptions) CorrelationReport {
+	days := PairDays(cycles, recoveries, sleeps, workouts)
+
+	names := map[string]bool{}
+	for _, d := range days {
+		for f := range d.Features {
+			names[f] = true
+		}
+	}
+	features := make([]string, 0, len(names))
+	for f := range names {
+		features = append(features, f)
+	}
+	sort.Strings(features)
+
+	confidence := opts.Confidence
+	if confidence <= 0 || confidence >= 1 {
+		confidence = 0.95
+	}
+	z := math.Sqrt2 * math.Erfinv(confidence)
+
+	report := CorrelationReport{Days: days}
+	for _, f := range features {
+		for _, outcome := range []Metric{RecoveryScore, HRV} {
+			var xs, ys []float64
+			for _, d := range days {
+				x, ok := d.Features[f]
+				if !ok {
+					continue
+				}
+				y := d.RecoveryScore
+				if outcome == HRV {
+					y = d.HRV
+				}
+				xs, ys = append(xs, x), append(ys, y)
+			}
+			c := Correlation{Feature: f, Outcome: outcome, N: len(xs)}
+			c.Pearson = estimate(pearson(xs, ys), len(xs), 1, z)
+			c.Spearman = estimate(pearson(ranks(xs), ranks(ys)), len(xs), 1.06, z)
+			report.Correlations = append(report.Correlations, c)
+		}
+	}
+	return report
+}
+
+// PairDays returns the features of each completed scored cycle followed
+// by a cycle with a scored recovery, paired with that recovery, in
+// chronological order.
+//
+// Workouts belong to the cycle during which they start. The night
+// following a cycle is the sleep of the next cycle's recovery, linked by
+// Recovery.CycleID and Recovery.SleepID. Unscored records and
+// recoveries of calibrating members are skipped.
+func PairDays(cycles []whoop.Cycle, recoveries []whoop.Recovery, sleeps []whoop.Sleep, workouts []whoop.Workout) []FeatureDay {
+	sorted := make([]*whoop.Cycle, 0, len(cycles))
+	for i := range cycles {
+		if cycles[i].Start != nil {
+			sorted = append(sorted, &cycles[i])
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(*sorted[j].Start) })
+
+	recoveryByCycle := map[int]*whoop.Recovery{}
+	for i := range recoveries {
+		r := &recoveries[i]
+		if r.Scored() && !r.Score.UserCalibrating {
+			recoveryByCycle[r.CycleID] = r
+		}
+	}
+	sleepByID := map[int]*whoop.Sleep{}
+	for i := range sleeps {
+		sleepByID[sleeps[i].ID] = &sleeps[i]
+	}
+
+	var days []FeatureDay
+	for i := 0; i+1 < len(sorted); i++ {
+		c, next := sorted[i], sorted[i+1]
+		r, ok := recoveryByCycle[next.ID]
+		if !ok || !c.Scored() || c.End == nil {
+			continue
+		}
+		d := FeatureDay{CycleID: c.ID, Features: map[string]float64{}, RecoveryScore: r.Score.RecoveryScore, HRV: r.Score.HrvRmssdMilli}
+		d.Date, _ = CycleDate(c)
+		d.Features[FeatureStrain] = c.Score.Strain
+
+		if s, ok := sleepByID[r.SleepID]; ok && s.Scored() {
+			d.Features[FeatureSleepHours] = s.TimeAsleep().Hours()
+			d.Features[FeatureSleepConsistency] = s.Score.SleepConsistencyPercentage
+		}
+
+		d.Features[FeatureNap] = 0
+		for _, s := range sleeps {
+			if s.Nap && s.Start != nil && within(*s.Start, c) {
+				d.Features[FeatureNap] = 1
+			}
+		}
+
+		loc := whoop.ParseOffset(c.TimezoneOffset)
+		midnight := time.Date(d.Date.Year(), d.Date.Month(), d.Date.Day(), 0, 0, 0, 0, loc)
+		var lastEnd *time.Time
+		for _, w := range workouts {
+			if !w.Scored() || w.Start == nil || !within(*w.Start, c) {
+				continue
+			}
+			d.Features[FeatureSportPrefix+w.Sport()] = 1
+			if w.End != nil && (lastEnd == nil || w.End.After(*lastEnd)) {
+				lastEnd = w.End
+			}
+		}
+		if lastEnd != nil {
+			d.Features[FeatureWorkoutEnd] = lastEnd.Sub(midnight).Hours()
+		}
+		days = append(days, d)
+	}
+
+	// Sports are indicators: days without a workout of a sport are zeros.
+	for f := range sportFeatures(days) {
+		for _, d := range days {
+			if _, ok := d.Features[f]; !ok {
+				d.Features[f] = 0
+			}
+		}
+	}
+	return days
+}
+
+func sportFeatures(days []FeatureDay) map[string]bool {
+	sports := map[string]bool{}
+	for _, d := range days {
+		for f := range d.Features {
+			if strings.HasPrefix(f, FeatureSportPrefix) {
+				sports[f] = true
+			}
+		}
+	}
+	return sports
+}
+
+// within reports whether t is within the cycle c.
+func within(t time.Time, c *whoop.Cycle) bool {
+	return !t.Before(*c.Start) && (c.End == nil || t.Before(*c.End))
+}
+
+// pearson returns the Pearson correlation coefficient of xs and ys. It
+// returns NaN if there are fewer than 2 pairs or no variation.
+func pearson(xs, ys []float64) float64 {
+	if len(xs) < 2 {
+		return math.NaN()
+	}
+	mx, _ := meanStdDev(xs)
+	my, _ := meanStdDev(ys)
+	var sxy, sxx, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// ranks returns the ranks of values, starting at 1, with ties given
+// their average rank.
+func ranks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return values[idx[a]] < values[idx[b]] })
+	r := make([]float64, len(values))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && values[idx[j+1]] == values[idx[i]] {
+			j++
+		}
+		avg := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			r[idx[k]] = avg
+		}
+		i = j + 1
+	}
+	return r
+}
+
+// estimate returns r with its confidence interval for n pairs, using the
+// Fisher transformation with a standard error of sqrt(variance/(n-3)) and
+// the normal quantile z.
+func estimate(r float64, n int, variance, z float64) Estimate {
+	if math.IsNaN(r) || n < 4 {
+		return Estimate{}
+	}
+	if math.Abs(r) >= 1 {
+		return Estimate{R: r, Low: r, High: r, Valid: true}
+	}
+	fr := math.Atanh(r)
+	se := math.Sqrt(variance / float64(n-3))
+	return Estimate{R: r, Low: math.Tanh(fr - z*se), High: math.Tanh(fr + z*se), Valid: true}
+}
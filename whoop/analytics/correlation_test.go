@@ -0,0 +1,142 @@
+package analytics
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestCorrelations(t *testing.T) {
+	var cycles []whoop.Cycle
+	var recoveries []whoop.Recovery
+	var sleeps []whoop.Sleep
+	var workouts []whoop.Workout
+	for d := 1; d <= 8; d++ {
+		cycles = append(cycles, cycle(d, d, float64(d)))
+		// The later the workout ends, the lower the next recovery.
+		end := time.Date(2022, 11, d, 22+d, 0, 0, 0, time.UTC) // 2pm + d hours at -08:00.
+		start := end.Add(-time.Hour)
+		w := whoop.Workout{Start: &start, End: &end, SportName: whooptest.Ptr("Running"), ScoreState: whooptest.Ptr("SCORED")}
+		workouts = append(workouts, w)
+		if d > 1 {
+			r := recovery(d, 100-float64(d)*10)
+			r.SleepID = d
+			recoveries = append(recoveries, r)
+			s := night(d, d, 7, 8)
+			s.Score.SleepConsistencyPercentage = float64(d)
+			sleeps = append(sleeps, s)
+		}
+	}
+	nap := night(100, 3, 1, 0)
+	nap.Nap = true
+	sleeps = append(sleeps, nap)
+
+	report := Correlations(cycles, recoveries, sleeps, workouts, CorrelationOptions{})
+
+	if len(report.Days) != 7 {
+		t.Fatalf("Correlations(): got %d days, want 7", len(report.Days))
+	}
+	first := report.Days[0]
+	if first.CycleID != 1 || first.RecoveryScore != 80 || !first.Date.Equal(day(1)) {
+		t.Errorf("Correlations(): got first day %+v, want cycle 1 on %v with recovery 80", first, day(1))
+	}
+	want := map[string]float64{
+		FeatureStrain:           1,
+		FeatureWorkoutEnd:       15,
+		FeatureSleepHours:       7,
+		FeatureSleepConsistency: 2,
+		FeatureNap:              0,
+		"sport:Running":         1,
+	}
+	if !reflect.DeepEqual(first.Features, want) {
+		t.Errorf("Correlations(): got features %v, want %v", first.Features, want)
+	}
+	if report.Days[1].Features[FeatureNap] != 0 || report.Days[2].Features[FeatureNap] != 1 {
+		t.Errorf("Correlations(): got naps %v, %v, want the nap in cycle 3", report.Days[1].Features, report.Days[2].Features)
+	}
+
+	var got *Correlation
+	for i, c := range report.Correlations {
+		if c.Feature == FeatureWorkoutEnd && c.Outcome == RecoveryScore {
+			got = &report.Correlations[i]
+		}
+	}
+	if got == nil {
+		t.Fatalf("Correlations(): no correlation of %s with %s", FeatureWorkoutEnd, RecoveryScore)
+	}
+	if got.N != 7 || math.Abs(got.Pearson.R+1) > 1e-9 || math.Abs(got.Spearman.R+1) > 1e-9 {
+		t.Errorf("Correlations(): got %+v, want N 7 and perfect negative correlations", got)
+	}
+	if !got.Pearson.Valid || got.Pearson.Low > got.Pearson.R || got.Pearson.High < got.Pearson.R {
+		t.Errorf("Correlations(): got Pearson %+v, want a valid interval around R", got.Pearson)
+	}
+
+	for _, c := range report.Correlations {
+		if c.Feature == FeatureSleepHours && c.Pearson.Valid {
+			t.Errorf("Correlations(): got valid %+v for a constant feature", c)
+		}
+	}
+}
+
+func TestCorrelations_workoutEnd(t *testing.T) {
+	at := func(d, h, m int) *time.Time {
+		t := time.Date(2022, 11, d, h, m, 0, 0, time.UTC)
+		return &t
+	}
+	cycles := []whoop.Cycle{cycle(1, 1, 10), cycle(2, 2, 10), cycle(3, 3, 10), cycle(4, 4, 10)}
+	// Cycle 1 lasts until 2am on November 2, local time.
+	cycles[0].End = at(2, 10, 0)
+	cycles[1].Start = at(2, 10, 0)
+	var recoveries []whoop.Recovery
+	for id := 2; id <= 4; id++ {
+		recoveries = append(recoveries, recovery(id, 50))
+	}
+	workout := func(start, end *time.Time) whoop.Workout {
+		return whoop.Workout{Start: start, End: end, SportName: whooptest.Ptr("Running"), ScoreState: whooptest.Ptr("SCORED")}
+	}
+	workouts := []whoop.Workout{
+		workout(at(2, 7, 45), at(2, 8, 30)), // 11:45pm to 12:30am.
+		workout(at(2, 4, 0), at(2, 5, 0)),   // 8pm to 9pm.
+		workout(at(3, 7, 15), at(3, 8, 0)),  // 11:15pm to midnight.
+	}
+
+	days := Correlations(cycles, recoveries, nil, workouts, CorrelationOptions{}).Days
+
+	if len(days) != 3 {
+		t.Fatalf("Correlations(): got %d days, want 3", len(days))
+	}
+	if got, ok := days[0].Features[FeatureWorkoutEnd]; !ok || got != 24.5 {
+		t.Errorf("Correlations(): got workout end %v, %v after midnight, want 24.5", got, ok)
+	}
+	if got, ok := days[1].Features[FeatureWorkoutEnd]; ok {
+		t.Errorf("Correlations(): got workout end %v on a day without workouts, want none", got)
+	}
+	if got, ok := days[2].Features[FeatureWorkoutEnd]; !ok || got != 0 {
+		t.Errorf("Correlations(): got workout end %v, %v at midnight, want 0", got, ok)
+	}
+}
+
+func TestRanks(t *testing.T) {
+	got := ranks([]float64{10, 30, 20, 30})
+	want := []float64{1, 3.5, 2, 3.5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ranks(): got %v, want %v", got, want)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	e := estimate(0.5, 28, 1, 1.959964)
+	if !e.Valid || math.Abs(e.Low-0.1556) > 1e-3 || math.Abs(e.High-0.7350) > 1e-3 {
+		t.Errorf("estimate(): got %+v, want [0.156, 0.735]", e)
+	}
+	if e := estimate(0.5, 3, 1, 1.96); e.Valid {
+		t.Errorf("estimate(): got %+v for 3 pairs, want invalid", e)
+	}
+	if e := estimate(math.NaN(), 10, 1, 1.96); e.Valid {
+		t.Errorf("estimate(): got %+v for NaN, want invalid", e)
+	}
+}
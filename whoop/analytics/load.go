@@ -0,0 +1,289 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// LoadSource selects the measure of daily training load.
+type LoadSource int
+
+const (
+	// CycleStrain uses the strain of the day's completed cycle. Days
+	// without a scored cycle have no data and are left out of averages.
+	CycleStrain LoadSource = iota
+
+	// WorkoutStrain uses the sum of the strain of the day's workouts.
+	// Days without workouts are rest days with a load of zero.
+	WorkoutStrain
+
+	// WorkoutKilojoules uses the sum of the kilojoules of the day's
+	// workouts. Days without workouts are rest days with a load of zero.
+	WorkoutKilojoules
+)
+
+// Load flags raised on days and weeks outside of the safe bands.
+const (
+	FlagACWRHigh     = "acwr_high"      // Rolling-average ACWR above the safe band.
+	FlagACWRLow      = "acwr_low"       // Rolling-average ACWR below the safe band.
+	FlagEWMAHigh     = "ewma_acwr_high" // EWMA ACWR above the safe band.
+	FlagEWMALow      = "ewma_acwr_low"  // EWMA ACWR below the safe band.
+	FlagMonotonyHigh = "monotony_high"  // Weekly training monotony above the limit.
+	FlagStrainHigh   = "strain_high"    // Weekly training strain above the limit.
+)
+
+// LoadOptions configures the training load model. The zero value uses
+// 7 and 28 day windows, a safe ACWR band of 0.8 to 1.3 and a monotony
+// limit of 2.
+type LoadOptions struct {
+	Source LoadSource
+
+	Acute   int // Length of the acute window, in days. Defaults to 7.
+	Chronic int // Length of the chronic window, in days. Defaults to 28.
+
+	// SafeLow and SafeHigh bound the safe ACWR band. Default to 0.8 and 1.3.
+	SafeLow, SafeHigh float64
+
+	// MonotonyHigh is the weekly monotony above which a week is flagged.
+	// Defaults to 2.
+	MonotonyHigh float64
+
+	// StrainHigh is the weekly Foster strain above which a week is
+	// flagged. Zero disables the flag, as strain depends on the source.
+	StrainHigh float64
+}
+
+func (o LoadOptions) withDefaults() LoadOptions {
+	if o.Acute <= 0 {
+		o.Acute = 7
+	}
+	if o.Chronic <= 0 {
+		o.Chronic = 28
+	}
+	if o.SafeLow == 0 {
+		o.SafeLow = 0.8
+	}
+	if o.SafeHigh == 0 {
+		o.SafeHigh = 1.3
+	}
+	if o.MonotonyHigh == 0 {
+		o.MonotonyHigh = 2
+	}
+	return o
+}
+
+// LoadDay is the training load of a day.
+type LoadDay struct {
+	Date    time.Time
+	Load    float64 // Load of the day.
+	Present bool    // False if the day has no data.
+
+	// Acute and Chronic are the rolling averages of the load over the
+	// acute and chronic windows ending on the day, and ACWR their ratio.
+	// ACWRValid is false until a full chronic window has elapsed, or if
+	// the chronic load is zero.
+	Acute, Chronic, ACWR float64
+	ACWRValid            bool
+
+	// EWMAAcute and EWMAChronic are the exponentially weighted moving
+	// averages of the load, with decay 2/(N+1) for a window of N days, and
+	// EWMAACWR their ratio, valid under the same conditions as ACWR.
+	EWMAAcute, EWMAChronic, EWMAACWR float64
+	EWMAValid                        bool
+
+	Flags []string // Flags raised for the day.
+}
+
+// LoadWeek is the training load of a week, starting on Monday.
+type LoadWeek struct {
+	Start time.Time
+	Days  int     // Days of the week with data.
+	Total float64 // Total load of the week.
+
+	// Monotony is the mean daily load of the week divided by its standard
+	// deviation, and Strain the total load multiplied by the monotony
+	// (Foster, 1998). MonotonyValid is false if the week has fewer than two
+	// days with data or no variation.
+	Monotony, Strain float64
+	MonotonyValid    bool
+
+	Flags []string // Flags raised for the week.
+}
+
+// LoadReport is the training load model over a period.
+type LoadReport struct {
+	Days  []LoadDay
+	Weeks []LoadWeek
+}
+
+// DailyLoad returns the daily series of training load from source. The
+// day of a workout is the local date on which it starts. Unscored records
+// and cycles in progress are skipped.
+func DailyLoad(cycles []whoop.Cycle, workouts []whoop.Workout, source LoadSource) Series {
+	loads := map[time.Time]float64{}
+	var first, last time.Time
+	add := func(d time.Time, v float64) {
+		loads[d] += v
+		if first.IsZero() || d.Before(first) {
+			first = d
+		}
+		if d.After(last) {
+			last = d
+		}
+	}
+	switch source {
+	case CycleStrain:
+		for i := range cycles {
+			c := &cycles[i]
+			if !c.Scored() || c.End == nil {
+				continue
+			}
+			if d, ok := CycleDate(c); ok {
+				add(d, c.Score.Strain)
+			}
+		}
+	case WorkoutStrain, WorkoutKilojoules:
+		for i := range workouts {
+			w := &workouts[i]
+			if !w.Scored() || w.Start == nil {
+				continue
+			}
+			v := w.Score.Strain
+			if source == WorkoutKilojoules {
+				v = w.Score.Kilojoule
+			}
+			add(Date(*w.Start, w.TimezoneOffset), v)
+		}
+	}
+
+	s := Series{Metric: "load"}
+	if first.IsZero() {
+		return s
+	}
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		v, ok := loads[d]
+		// Days without workouts are rest days.
+		if !ok && source != CycleStrain {
+			ok = true
+		}
+		s.Days = append(s.Days, Day{Date: d, Value: v, Present: ok})
+	}
+	return s
+}
+
+// TrainingLoad computes the acute:chronic workload ratio of every day,
+// using both rolling averages and exponentially weighted moving averages,
+// and the Foster monotony and strain of every week. Days and weeks outside
+// of the safe bands of opts are flagged.
+func TrainingLoad(cycles []whoop.Cycle, workouts []whoop.Workout, opts LoadOptions) LoadReport {
+	opts = opts.withDefaults()
+	s := DailyLoad(cycles, workouts, opts.Source)
+	return LoadReport{Days: loadDays(s, opts), Weeks: loadWeeks(s, opts)}
+}
+
+func loadDays(s Series, opts LoadOptions) []LoadDay {
+	acuteDecay := 2 / (float64(opts.Acute) + 1)
+	chronicDecay := 2 / (float64(opts.Chronic) + 1)
+	days := make([]LoadDay, len(s.Days))
+	var ewmaAcute, ewmaChronic float64
+	seeded := false
+	for i, d := range s.Days {
+		ld := LoadDay{Date: d.Date, Load: d.Value, Present: d.Present}
+
+		// Missing days carry the averages forward.
+		if d.Present {
+			if !seeded {
+				ewmaAcute, ewmaChronic, seeded = d.Value, d.Value, true
+			} else {
+				ewmaAcute = acuteDecay*d.Value + (1-acuteDecay)*ewmaAcute
+				ewmaChronic = chronicDecay*d.Value + (1-chronicDecay)*ewmaChronic
+			}
+		}
+		ld.EWMAAcute, ld.EWMAChronic = ewmaAcute, ewmaChronic
+
+		ld.Acute, _ = windowMean(s.Days, i, opts.Acute)
+		ld.Chronic, _ = windowMean(s.Days, i, opts.Chronic)
+		full := i+1 >= opts.Chronic
+		if full && ld.Chronic > 0 {
+			ld.ACWR, ld.ACWRValid = ld.Acute/ld.Chronic, true
+			ld.Flags = appendBand(ld.Flags, ld.ACWR, opts, FlagACWRLow, FlagACWRHigh)
+		}
+		if full && ld.EWMAChronic > 0 {
+			ld.EWMAACWR, ld.EWMAValid = ld.EWMAAcute/ld.EWMAChronic, true
+			ld.Flags = appendBand(ld.Flags, ld.EWMAACWR, opts, FlagEWMALow, FlagEWMAHigh)
+		}
+		days[i] = ld
+	}
+	return days
+}
+
+// windowMean returns the mean of the days with data in the window of n
+// days ending on days[i], and their count.
+func windowMean(days []Day, i, n int) (float64, int) {
+	var sum float64
+	var count int
+	for j := i - n + 1; j <= i; j++ {
+		if j >= 0 && days[j].Present {
+			sum += days[j].Value
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return sum / float64(count), count
+}
+
+func appendBand(flags []string, v float64, opts LoadOptions, low, high string) []string {
+	switch {
+	case v < opts.SafeLow:
+		return append(flags, low)
+	case v > opts.SafeHigh:
+		return append(flags, high)
+	}
+	return flags
+}
+
+func loadWeeks(s Series, opts LoadOptions) []LoadWeek {
+	var weeks []LoadWeek
+	var values []float64
+	flush := func() {
+		if len(weeks) == 0 {
+			return
+		}
+		w := &weeks[len(weeks)-1]
+		w.Days = len(values)
+		mean, sd := meanStdDev(values)
+		w.Total = mean * float64(len(values))
+		if len(values) >= 2 && sd > 0 {
+			w.Monotony, w.MonotonyValid = mean/sd, true
+			w.Strain = w.Total * w.Monotony
+			if w.Monotony > opts.MonotonyHigh {
+				w.Flags = append(w.Flags, FlagMonotonyHigh)
+			}
+			if opts.StrainHigh > 0 && w.Strain > opts.StrainHigh {
+				w.Flags = append(w.Flags, FlagStrainHigh)
+			}
+		}
+		values = values[:0]
+	}
+	for _, d := range s.Days {
+		start := WeekStart(d.Date)
+		if len(weeks) == 0 || !weeks[len(weeks)-1].Start.Equal(start) {
+			flush()
+			weeks = append(weeks, LoadWeek{Start: start})
+		}
+		if d.Present {
+			values = append(values, d.Value)
+		}
+	}
+	flush()
+	return weeks
+}
+
+// WeekStart returns the Monday of the week of date.
+func WeekStart(date time.Time) time.Time {
+	offset := (int(date.Weekday()) + 6) % 7
+	return date.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func workout(d int, strain, kj float64) whoop.Workout {
+	start := time.Date(2022, 11, d, 17, 0, 0, 0, time.UTC)
+	w := whoop.Workout{Start: &start, ScoreState: whooptest.Ptr("SCORED")}
+	w.Score.Strain = strain
+	w.Score.Kilojoule = kj
+	return w
+}
+
+func TestDailyLoad(t *testing.T) {
+	pending := workout(2, 20, 0)
+	pending.ScoreState = whooptest.Ptr("PENDING_SCORE")
+	s := DailyLoad(nil, []whoop.Workout{workout(1, 10, 500), workout(1, 5, 300), pending, workout(3, 8, 400)}, WorkoutKilojoules)
+	want := []Day{{day(1), 800, true}, {day(2), 0, true}, {day(3), 400, true}}
+	if len(s.Days) != len(want) {
+		t.Fatalf("DailyLoad(): got %+v, want %+v", s.Days, want)
+	}
+	for i := range want {
+		if s.Days[i] != want[i] {
+			t.Errorf("DailyLoad(): day %v: got %+v, want %+v", i, s.Days[i], want[i])
+		}
+	}
+
+	s = DailyLoad([]whoop.Cycle{cycle(1, 1, 10), cycle(2, 3, 12)}, nil, CycleStrain)
+	if len(s.Days) != 3 || s.Days[1].Present {
+		t.Errorf("DailyLoad(): expected the day without a cycle to be missing, got %+v", s.Days)
+	}
+}
+
+func TestTrainingLoad(t *testing.T) {
+	var cycles []whoop.Cycle
+	for d := 1; d <= 28; d++ {
+		cycles = append(cycles, cycle(d, d, 10))
+	}
+	// A spike in the last week.
+	for i := 21; i < 28; i++ {
+		cycles[i].Score.Strain = 20
+	}
+	r := TrainingLoad(cycles, nil, LoadOptions{})
+
+	if len(r.Days) != 28 {
+		t.Fatalf("TrainingLoad(): expected 28 days, got %v", len(r.Days))
+	}
+	if r.Days[26].ACWRValid {
+		t.Error("TrainingLoad(): expected ACWR to be invalid before a full chronic window")
+	}
+	last := r.Days[27]
+	if !last.ACWRValid || last.Acute != 20 || last.Chronic != 12.5 || last.ACWR != 1.6 {
+		t.Errorf("TrainingLoad(): got last day %+v", last)
+	}
+	if !last.EWMAValid || last.EWMAACWR <= 1.3 {
+		t.Errorf("TrainingLoad(): expected EWMA ACWR above the safe band, got %v", last.EWMAACWR)
+	}
+	if len(last.Flags) != 2 || last.Flags[0] != FlagACWRHigh || last.Flags[1] != FlagEWMAHigh {
+		t.Errorf("TrainingLoad(): got flags %v", last.Flags)
+	}
+
+	// Nov 1st 2022 is a Tuesday.
+	if len(r.Weeks) != 5 || !r.Weeks[0].Start.Equal(time.Date(2022, 10, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("TrainingLoad(): got weeks %+v", r.Weeks)
+	}
+	if w := r.Weeks[1]; w.Days != 7 || w.Total != 70 || w.MonotonyValid {
+		t.Errorf("TrainingLoad(): expected constant week to have no monotony, got %+v", w)
+	}
+	// The week of the 21st: one day at 10 then six at 20.
+	w := r.Weeks[3]
+	mean, sd := 130.0/7, math.Sqrt((6*math.Pow(20-130.0/7, 2)+math.Pow(10-130.0/7, 2))/6)
+	if !w.MonotonyValid || math.Abs(w.Monotony-mean/sd) > 1e-9 || math.Abs(w.Strain-130*mean/sd) > 1e-9 {
+		t.Errorf("TrainingLoad(): got week %+v, want monotony %v", w, mean/sd)
+	}
+	if len(w.Flags) != 1 || w.Flags[0] != FlagMonotonyHigh {
+		t.Errorf("TrainingLoad(): expected monotonous week to be flagged, got %v", w.Flags)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	for d := 28; d <= 30; d++ {
+		date := time.Date(2022, 11, d, 0, 0, 0, 0, time.UTC)
+		if got := WeekStart(date); !got.Equal(day(28)) {
+			t.Errorf("WeekStart(%v): got %v, want Monday 28th", date, got)
+		}
+	}
+	if got := WeekStart(day(27)); !got.Equal(day(21)) {
+		t.Errorf("WeekStart(Sunday 27th): got %v, want Monday 21st", got)
+	}
+}
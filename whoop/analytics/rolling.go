@@ -0,0 +1,156 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// DefaultWindows are the default lengths of rolling windows, in days.
+var DefaultWindows = []int{7, 14, 30, 60}
+
+// defaultMinCoverage is the default minimum fraction of days of a window
+// with data.
+const defaultMinCoverage = 0.5
+
+// Options configures rolling statistics.
+type Options struct {
+	// Windows lists the lengths of the rolling windows, in days.
+	// Defaults to DefaultWindows.
+	Windows []int
+
+	// MinCoverage is the minimum fraction of the days of a window which
+	// must have data for its statistics to be valid. Defaults to 0.5.
+	// At least two days with data are always required.
+	MinCoverage float64
+}
+
+func (o Options) windows() []int {
+	if len(o.Windows) == 0 {
+		return DefaultWindows
+	}
+	return o.Windows
+}
+
+// minSamples returns the minimum number of days with data of a window.
+func (o Options) minSamples(window int) int {
+	coverage := o.MinCoverage
+	if coverage <= 0 {
+		coverage = defaultMinCoverage
+	}
+	n := int(math.Ceil(coverage * float64(window)))
+	if n < 2 {
+		n = 2
+	}
+	return n
+}
+
+// Stats are the rolling statistics of a metric on a day. They are computed
+// over the window of days immediately before the day, which is the
+// member's baseline the day is compared against; the day itself is not
+// part of its window.
+type Stats struct {
+	Date    time.Time
+	Value   float64 // Value on the day. Zero if the day is missing.
+	Present bool    // False if the day is missing.
+
+	Window int  // Length of the window, in days.
+	N      int  // Number of days with data in the window.
+	Valid  bool // True if the window has enough days with data. The statistics are zero otherwise.
+
+	Mean   float64
+	Median float64
+	StdDev float64 // Sample standard deviation.
+}
+
+// ZScore returns the standard score of the day's value against its window.
+// It returns false if the day is missing, the statistics are not valid, or
+// the window has no variation.
+func (s Stats) ZScore() (float64, bool) {
+	if !s.Present || !s.Valid || s.StdDev == 0 {
+		return 0, false
+	}
+	return (s.Value - s.Mean) / s.StdDev, true
+}
+
+// Rolling returns the statistics of every day of s over windows of the
+// given length, requiring minSamples days with data for them to be valid.
+// A minSamples lower than 2 is treated as 2.
+func (s Series) Rolling(window, minSamples int) []Stats {
+	if minSamples < 2 {
+		minSamples = 2
+	}
+	stats := make([]Stats, len(s.Days))
+	values := make([]float64, 0, window)
+	for i, d := range s.Days {
+		st := Stats{Date: d.Date, Value: d.Value, Present: d.Present, Window: window}
+		values = values[:0]
+		for j := i - window; j < i; j++ {
+			if j >= 0 && s.Days[j].Present {
+				values = append(values, s.Days[j].Value)
+			}
+		}
+		st.N = len(values)
+		if st.N >= minSamples {
+			st.Valid = true
+			st.Mean, st.StdDev = meanStdDev(values)
+			st.Median = median(values)
+		}
+		stats[i] = st
+	}
+	return stats
+}
+
+// Report holds the daily series and rolling statistics of every metric.
+type Report struct {
+	Series map[Metric]Series
+	Stats  map[Metric]map[int][]Stats // By metric and window length.
+}
+
+// Baselines computes the rolling statistics of every metric over the
+// windows of opts. See DailySeries for the records taken into account.
+func Baselines(cycles []whoop.Cycle, recoveries []whoop.Recovery, sleeps []whoop.Sleep, opts Options) Report {
+	r := Report{Series: DailySeries(cycles, recoveries, sleeps), Stats: map[Metric]map[int][]Stats{}}
+	for m, s := range r.Series {
+		r.Stats[m] = map[int][]Stats{}
+		for _, w := range opts.windows() {
+			r.Stats[m][w] = s.Rolling(w, opts.minSamples(w))
+		}
+	}
+	return r
+}
+
+// meanStdDev returns the mean and the sample standard deviation of values.
+func meanStdDev(values []float64) (mean, stddev float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var ss float64
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(values)-1))
+}
+
+// median returns the median of values, without modifying them.
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,68 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestSeries_Rolling(t *testing.T) {
+	s := Series{Metric: HRV}
+	for i, v := range []float64{50, 60, 0, 70, 40} {
+		s.Days = append(s.Days, Day{Date: day(i + 1), Value: v, Present: v != 0})
+	}
+	stats := s.Rolling(3, 2)
+
+	if stats[1].Valid || stats[1].N != 1 {
+		t.Errorf("Rolling(): expected day 2 to have too few samples, got %+v", stats[1])
+	}
+	// Window of day 4: 50, 60 and a missing day.
+	if st := stats[3]; !st.Valid || st.N != 2 || st.Mean != 55 || st.Median != 55 || math.Abs(st.StdDev-7.0711) > 1e-4 {
+		t.Errorf("Rolling(): got day 4 stats %+v", st)
+	}
+	if z, ok := stats[3].ZScore(); !ok || math.Abs(z-2.1213) > 1e-4 {
+		t.Errorf("Stats.ZScore(): got %v, %v, want 2.1213", z, ok)
+	}
+	// Window of day 5: 60, a missing day and 70.
+	if st := stats[4]; st.Mean != 65 || st.Median != 65 {
+		t.Errorf("Rolling(): got day 5 stats %+v", st)
+	}
+	if _, ok := stats[2].ZScore(); ok {
+		t.Error("Stats.ZScore(): expected no z-score for a missing day")
+	}
+}
+
+func TestMedian(t *testing.T) {
+	values := []float64{3, 1, 2}
+	if got := median(values); got != 2 {
+		t.Errorf("median(): got %v, want 2", got)
+	}
+	if values[0] != 3 {
+		t.Error("median(): expected values not to be modified")
+	}
+	if got := median([]float64{4, 1, 3, 2}); got != 2.5 {
+		t.Errorf("median(): got %v, want 2.5", got)
+	}
+}
+
+func TestBaselines(t *testing.T) {
+	var cycles []whoop.Cycle
+	for d := 1; d <= 20; d++ {
+		cycles = append(cycles, cycle(d, d, float64(d%3)))
+	}
+	r := Baselines(cycles, nil, nil, Options{Windows: []int{7, 14}})
+	if len(r.Stats[Strain]) != 2 {
+		t.Fatalf("Baselines(): expected 2 windows, got %v", len(r.Stats[Strain]))
+	}
+	week := r.Stats[Strain][7]
+	if len(week) != 20 {
+		t.Fatalf("Baselines(): expected 20 days, got %v", len(week))
+	}
+	if week[3].Valid || !week[4].Valid {
+		t.Errorf("Baselines(): expected 4 days of data to be required by a 7 day window, got %v and %v", week[3].N, week[4].N)
+	}
+	if got := r.Stats[Strain][14][19].N; got != 14 {
+		t.Errorf("Baselines(): expected 14 samples, got %v", got)
+	}
+}
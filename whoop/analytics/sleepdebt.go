@@ -0,0 +1,116 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// SleepEntry is an entry of a SleepLedger: a night of sleep or a nap.
+type SleepEntry struct {
+	SleepID    int
+	Date       time.Time // Day of the sleep. See SleepDate.
+	Start, End time.Time
+	Nap        bool
+
+	// Need is the total sleep needed for a night, and Baseline, FromDebt,
+	// FromStrain and FromNaps its components as reported by
+	// Sleep.Score.SleepNeeded. FromNaps is zero or negative. They are
+	// zero for naps.
+	Need                                     time.Duration
+	Baseline, FromDebt, FromStrain, FromNaps time.Duration
+
+	// Achieved is the time asleep: light, slow wave and REM sleep.
+	Achieved time.Duration
+
+	// Balance is Achieved minus Need for a night, negative when the night
+	// fell short of the need. It is zero for naps.
+	Balance time.Duration
+
+	// Debt is the cumulative sleep debt after the entry.
+	Debt time.Duration
+}
+
+// SleepLedger is the chronological ledger of a member's sleeps.
+type SleepLedger struct {
+	Entries []SleepEntry
+
+	// Debt is the cumulative sleep debt after the latest entry.
+	Debt time.Duration
+
+	// LatestNeed is the need of the latest night, and LatestEfficiency
+	// its sleep efficiency as a fraction.
+	LatestNeed       time.Duration
+	LatestEfficiency float64
+}
+
+// SleepDebt walks the scored sleeps chronologically, naps included, and
+// returns their ledger.
+//
+// The cumulative debt grows by the baseline need of each night not
+// achieved that night, shrinks by the sleep achieved beyond the baseline,
+// and shrinks by the time asleep during naps. It never goes below zero.
+// The baseline is used rather than the total need, since the total need
+// already includes WHOOP's own estimate of the debt.
+func SleepDebt(sleeps []whoop.Sleep) SleepLedger {
+	scored := make([]*whoop.Sleep, 0, len(sleeps))
+	for i := range sleeps {
+		s := &sleeps[i]
+		if s.Scored() && s.Start != nil && s.End != nil {
+			scored = append(scored, s)
+		}
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Start.Before(*scored[j].Start) })
+
+	var l SleepLedger
+	for _, s := range scored {
+		e := SleepEntry{
+			SleepID:  s.ID,
+			Start:    *s.Start,
+			End:      *s.End,
+			Nap:      s.Nap,
+			Achieved: s.TimeAsleep(),
+		}
+		e.Date, _ = SleepDate(s)
+		if s.Nap {
+			l.Debt -= e.Achieved
+		} else {
+			need := s.Score.SleepNeeded
+			e.Baseline = millis(need.BaselineMilli)
+			e.FromDebt = millis(need.NeedFromSleepDebtMilli)
+			e.FromStrain = millis(need.NeedFromRecentStrainMilli)
+			e.FromNaps = millis(need.NeedFromRecentNapMilli)
+			e.Need = e.Baseline + e.FromDebt + e.FromStrain + e.FromNaps
+			e.Balance = e.Achieved - e.Need
+			l.Debt += e.Baseline - e.Achieved
+			l.LatestNeed = e.Need
+			l.LatestEfficiency = s.Score.SleepEfficiencyPercentage / 100
+		}
+		if l.Debt < 0 {
+			l.Debt = 0
+		}
+		e.Debt = l.Debt
+		l.Entries = append(l.Entries, e)
+	}
+	return l
+}
+
+// RecommendedBedtime returns the time to go to bed to wake at wake having
+// slept the latest need. The time in bed is the need divided by the
+// latest sleep efficiency, when known. It returns false if the ledger has
+// no night.
+func (l SleepLedger) RecommendedBedtime(wake time.Time) (time.Time, bool) {
+	if l.LatestNeed <= 0 {
+		return time.Time{}, false
+	}
+	inBed := l.LatestNeed
+	if l.LatestEfficiency > 0 && l.LatestEfficiency <= 1 {
+		inBed = time.Duration(float64(l.LatestNeed) / l.LatestEfficiency)
+	}
+	return wake.Add(-inBed.Round(time.Minute)), true
+}
+
+func millis(ms int) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
@@ -0,0 +1,78 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func night(id, d int, asleepHours, baselineHours float64) whoop.Sleep {
+	start := time.Date(2022, 11, d, 7, 0, 0, 0, time.UTC)
+	end := start.Add(9 * time.Hour)
+	s := whoop.Sleep{ID: id, Start: &start, End: &end, TimezoneOffset: whooptest.Ptr("-08:00"), ScoreState: whooptest.Ptr("SCORED")}
+	s.Score.StageSummary.TotalLightSleepTimeMilli = int(asleepHours * float64(time.Hour/time.Millisecond))
+	s.Score.SleepNeeded.BaselineMilli = int(baselineHours * float64(time.Hour/time.Millisecond))
+	s.Score.SleepEfficiencyPercentage = 90
+	return s
+}
+
+func TestSleepDebt(t *testing.T) {
+	second := night(2, 21, 6, 8)
+	second.Score.SleepNeeded.NeedFromSleepDebtMilli = int(time.Hour / time.Millisecond)
+	second.Score.SleepNeeded.NeedFromRecentNapMilli = -int(15 * time.Minute / time.Millisecond)
+	napStart := time.Date(2022, 11, 21, 21, 0, 0, 0, time.UTC)
+	napEnd := napStart.Add(45 * time.Minute)
+	nap := whoop.Sleep{ID: 3, Start: &napStart, End: &napEnd, Nap: true, ScoreState: whooptest.Ptr("SCORED")}
+	nap.Score.StageSummary.TotalLightSleepTimeMilli = int(30 * time.Minute / time.Millisecond)
+	unscored := night(5, 23, 1, 8)
+	unscored.ScoreState = whooptest.Ptr("UNSCORABLE")
+
+	// Out of order, as returned by the API.
+	l := SleepDebt([]whoop.Sleep{night(4, 22, 9, 8), nap, second, night(1, 20, 7, 8), unscored})
+
+	if len(l.Entries) != 4 {
+		t.Fatalf("SleepDebt(): expected 4 entries, got %v", len(l.Entries))
+	}
+	want := []struct {
+		id      int
+		nap     bool
+		balance time.Duration
+		debt    time.Duration
+	}{
+		{1, false, -time.Hour, time.Hour},
+		{2, false, -165 * time.Minute, 3 * time.Hour},
+		{3, true, 0, 150 * time.Minute},
+		{4, false, time.Hour, 90 * time.Minute},
+	}
+	for i, w := range want {
+		e := l.Entries[i]
+		if e.SleepID != w.id || e.Nap != w.nap || e.Balance != w.balance || e.Debt != w.debt {
+			t.Errorf("SleepDebt(): entry %v: got %+v, want %+v", i, e, w)
+		}
+	}
+	if e := l.Entries[1]; e.Need != 8*time.Hour+45*time.Minute || e.FromNaps != -15*time.Minute {
+		t.Errorf("SleepDebt(): got need %v from naps %v", e.Need, e.FromNaps)
+	}
+	if l.Debt != 90*time.Minute || l.LatestNeed != 8*time.Hour {
+		t.Errorf("SleepDebt(): got debt %v and latest need %v", l.Debt, l.LatestNeed)
+	}
+
+	wake := time.Date(2022, 11, 23, 6, 30, 0, 0, time.UTC)
+	bed, ok := l.RecommendedBedtime(wake)
+	// 8h at 90% efficiency is 8h53m in bed.
+	if want := time.Date(2022, 11, 22, 21, 37, 0, 0, time.UTC); !ok || !bed.Equal(want) {
+		t.Errorf("RecommendedBedtime(): got %v, %v, want %v", bed, ok, want)
+	}
+}
+
+func TestSleepDebt_floor(t *testing.T) {
+	l := SleepDebt([]whoop.Sleep{night(1, 20, 10, 8)})
+	if l.Debt != 0 {
+		t.Errorf("SleepDebt(): expected debt not to go below zero, got %v", l.Debt)
+	}
+	if _, ok := (SleepLedger{}).RecommendedBedtime(time.Now()); ok {
+		t.Error("RecommendedBedtime(): expected false without nights")
+	}
+}
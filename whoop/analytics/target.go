@@ -0,0 +1,179 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// MaxStrain is the maximum strain score.
+const MaxStrain = 21
+
+// TargetRange maps recovery scores to a target strain range, relative to
+// the member's baseline strain.
+type TargetRange struct {
+	// MinRecovery is the lowest recovery score the range applies to.
+	MinRecovery float64
+
+	// Low and High are added to the baseline strain to bound the target
+	// range.
+	Low, High float64
+}
+
+// DefaultTargetRanges is the default mapping from recovery to target
+// strain:
+//
+//   - Green (67% and above): from the baseline to 3 above it, to build
+//     fitness when the body is ready for it.
+//   - Yellow (34% to 66%): from 2 below the baseline to 1 above it, to
+//     maintain fitness.
+//   - Red (below 34%): from 5 to 2 below the baseline, to recover.
+var DefaultTargetRanges = []TargetRange{
+	{MinRecovery: 67, Low: 0, High: 3},
+	{MinRecovery: 34, Low: -2, High: 1},
+	{MinRecovery: 0, Low: -5, High: -2},
+}
+
+// TargetStatus is the progress of the day's strain towards its target.
+type TargetStatus string
+
+// Target statuses.
+const (
+	TargetBelow       TargetStatus = "below"       // Strain below the target range.
+	TargetInRange     TargetStatus = "in_range"    // Strain within the target range.
+	TargetOverreached TargetStatus = "overreached" // Strain above the target range.
+)
+
+// TargetOptions configures StrainTarget. The zero value uses
+// DefaultTargetRanges and the strain of the last 28 days.
+type TargetOptions struct {
+	// Ranges maps recovery scores to target ranges. The range with the
+	// highest MinRecovery at most the recovery score applies. Defaults to
+	// DefaultTargetRanges.
+	Ranges []TargetRange
+
+	// Window is the number of days of completed cycles the baseline strain
+	// is averaged over. Defaults to 28.
+	Window int
+
+	// MinCycles is the number of completed cycles in the window needed to
+	// compute the baseline. Defaults to 7.
+	MinCycles int
+
+	// DefaultBaseline is the baseline strain used when there are fewer
+	// than MinCycles cycles. Defaults to 10.
+	DefaultBaseline float64
+}
+
+// Target is the recommended strain range of a day and the progress
+// towards it.
+type Target struct {
+	Date     time.Time // Day of the recovery. See RecoveryDate.
+	Recovery float64   // Recovery score.
+	Band     Band
+
+	// Baseline is the mean strain of the BaselineCycles completed cycles
+	// in the window before the day, or the default baseline if there are
+	// too few of them.
+	Baseline       float64
+	BaselineCycles int
+
+	// Low and High bound the target strain range, within 0 to MaxStrain.
+	Low, High float64
+
+	// Strain is the strain of the day's cycle so far, and Remaining the
+	// strain left to reach the target range.
+	Strain    float64
+	Remaining float64
+
+	Status TargetStatus
+
+	// Overreach is the strain above the target range.
+	Overreach float64
+}
+
+// StrainTarget recommends a target strain range for the day of recovery
+// r, and tracks the progress of the strain of its cycle, usually in
+// progress, towards it.
+//
+// The range is the member's baseline strain, the mean strain of the
+// completed cycles of the last Window days, offset by the TargetRange of
+// the recovery score. It returns false if r is not scored or the member
+// is calibrating.
+func StrainTarget(r *whoop.Recovery, cycles []whoop.Cycle, opts TargetOptions) (Target, bool) {
+	if !r.Scored() || r.Score.UserCalibrating {
+		return Target{}, false
+	}
+	ranges := opts.Ranges
+	if len(ranges) == 0 {
+		ranges = DefaultTargetRanges
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = 28
+	}
+	minCycles := opts.MinCycles
+	if minCycles <= 0 {
+		minCycles = 7
+	}
+	baseline := opts.DefaultBaseline
+	if baseline <= 0 {
+		baseline = 10
+	}
+
+	byID := map[int]*whoop.Cycle{}
+	for i := range cycles {
+		byID[cycles[i].ID] = &cycles[i]
+	}
+	t := Target{Recovery: r.Score.RecoveryScore, Band: RecoveryBand(r.Score.RecoveryScore), Baseline: baseline}
+	t.Date, _ = RecoveryDate(r, byID)
+	if c, ok := byID[r.CycleID]; ok && c.Scored() {
+		t.Strain = c.Score.Strain
+	}
+
+	var strains []float64
+	from := t.Date.AddDate(0, 0, -window)
+	for i := range cycles {
+		c := &cycles[i]
+		if c.ID == r.CycleID || c.End == nil || !c.Scored() {
+			continue
+		}
+		if date, ok := CycleDate(c); ok && !date.Before(from) && date.Before(t.Date) {
+			strains = append(strains, c.Score.Strain)
+		}
+	}
+	if len(strains) >= minCycles {
+		t.Baseline, _ = meanStdDev(strains)
+		t.BaselineCycles = len(strains)
+	}
+
+	sorted := append([]TargetRange(nil), ranges...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].MinRecovery > sorted[j].MinRecovery })
+	rng := sorted[len(sorted)-1]
+	for _, tr := range sorted {
+		if t.Recovery >= tr.MinRecovery {
+			rng = tr
+			break
+		}
+	}
+	t.Low = clamp(t.Baseline+rng.Low, 0, MaxStrain)
+	t.High = clamp(t.Baseline+rng.High, t.Low, MaxStrain)
+
+	switch {
+	case t.Strain > t.High:
+		t.Status = TargetOverreached
+		t.Overreach = t.Strain - t.High
+	case t.Strain >= t.Low:
+		t.Status = TargetInRange
+	default:
+		t.Status = TargetBelow
+		t.Remaining = t.Low - t.Strain
+	}
+	return t, true
+}
+
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
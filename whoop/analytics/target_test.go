@@ -0,0 +1,92 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// targetHistory returns 14 completed cycles with a strain of 12 and the
+// cycle in progress on day 15 with the given strain.
+func targetHistory(strain float64) []whoop.Cycle {
+	var cycles []whoop.Cycle
+	for d := 1; d <= 14; d++ {
+		cycles = append(cycles, cycle(d, d, 12))
+	}
+	current := cycle(15, 15, strain)
+	current.End = nil
+	return append(cycles, current)
+}
+
+func TestStrainTarget(t *testing.T) {
+	tests := []struct {
+		name      string
+		score     float64
+		strain    float64
+		low, high float64
+		status    TargetStatus
+		remaining float64
+		overreach float64
+	}{
+		{"green below", 80, 5, 12, 15, TargetBelow, 7, 0},
+		{"green in range", 80, 13.5, 12, 15, TargetInRange, 0, 0},
+		{"green overreached", 80, 16, 12, 15, TargetOverreached, 0, 1},
+		{"yellow", 50, 10, 10, 13, TargetInRange, 0, 0},
+		{"red overreached", 20, 12, 7, 10, TargetOverreached, 0, 2},
+	}
+	for _, tt := range tests {
+		got, ok := StrainTarget(whooptest.Ptr(recovery(15, tt.score)), targetHistory(tt.strain), TargetOptions{})
+		if !ok {
+			t.Fatalf("StrainTarget(%s): got false, want true", tt.name)
+		}
+		if !got.Date.Equal(day(15)) || got.Baseline != 12 || got.BaselineCycles != 14 || got.Band != RecoveryBand(tt.score) {
+			t.Errorf("StrainTarget(%s): got %+v, want a baseline of 12 over 14 cycles on %v", tt.name, got, day(15))
+		}
+		if got.Low != tt.low || got.High != tt.high || got.Strain != tt.strain || got.Status != tt.status ||
+			math.Abs(got.Remaining-tt.remaining) > 1e-9 || math.Abs(got.Overreach-tt.overreach) > 1e-9 {
+			t.Errorf("StrainTarget(%s): got %+v, want range %v-%v, status %v, remaining %v, overreach %v", tt.name, got, tt.low, tt.high, tt.status, tt.remaining, tt.overreach)
+		}
+	}
+}
+
+func TestStrainTarget_defaultBaseline(t *testing.T) {
+	cycles := targetHistory(0)[10:] // Days 11 to 14 and the cycle in progress.
+	got, ok := StrainTarget(whooptest.Ptr(recovery(15, 90)), cycles, TargetOptions{})
+	if !ok || got.Baseline != 10 || got.BaselineCycles != 0 || got.Low != 10 || got.High != 13 {
+		t.Errorf("StrainTarget(): got %+v, %v, want the default baseline of 10", got, ok)
+	}
+}
+
+func TestStrainTarget_options(t *testing.T) {
+	cycles := targetHistory(0)
+	for i := range cycles {
+		cycles[i].Score.Strain = 20
+	}
+	opts := TargetOptions{
+		Ranges: []TargetRange{{MinRecovery: 0, Low: -1, High: 1}, {MinRecovery: 90, Low: 0, High: 4}},
+		Window: 7,
+	}
+	got, ok := StrainTarget(whooptest.Ptr(recovery(15, 95)), cycles, opts)
+	if !ok || got.BaselineCycles != 7 || got.Low != 20 || got.High != MaxStrain {
+		t.Errorf("StrainTarget(): got %+v, %v, want a range of 20-21 from 7 cycles", got, ok)
+	}
+	got, _ = StrainTarget(whooptest.Ptr(recovery(15, 60)), cycles, opts)
+	if got.Low != 19 || got.High != MaxStrain {
+		t.Errorf("StrainTarget(): got %+v, want a range of 19-21", got)
+	}
+}
+
+func TestStrainTarget_unscored(t *testing.T) {
+	pending := recovery(15, 80)
+	pending.ScoreState = whooptest.Ptr("PENDING_SCORE")
+	if _, ok := StrainTarget(&pending, targetHistory(0), TargetOptions{}); ok {
+		t.Errorf("StrainTarget(): got true for a pending recovery, want false")
+	}
+	calibrating := recovery(15, 80)
+	calibrating.Score.UserCalibrating = true
+	if _, ok := StrainTarget(&calibrating, targetHistory(0), TargetOptions{}); ok {
+		t.Errorf("StrainTarget(): got true for a calibrating member, want false")
+	}
+}
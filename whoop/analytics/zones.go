@@ -0,0 +1,182 @@
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// ZoneDurations are the times spent in heart rate zones 0 to 5, as
+// reported by Workout.Score.ZoneDuration.
+type ZoneDurations [6]time.Duration
+
+// Total returns the total time of z.
+func (z ZoneDurations) Total() time.Duration {
+	var total time.Duration
+	for _, d := range z {
+		total += d
+	}
+	return total
+}
+
+// Low returns the time in zones 1 and 2, below the first threshold.
+func (z ZoneDurations) Low() time.Duration { return z[1] + z[2] }
+
+// Threshold returns the time in zone 3, between the thresholds.
+func (z ZoneDurations) Threshold() time.Duration { return z[3] }
+
+// High returns the time in zones 4 and 5, above the second threshold.
+func (z ZoneDurations) High() time.Duration { return z[4] + z[5] }
+
+// PolarizationIndex returns the polarization index of z (Treff et al.,
+// 2019): log10(low/threshold × high × 100), where low, threshold and high
+// are the fractions of time in zones 1-2, 3 and 4-5. A high fraction of
+// zero is replaced by 0.01. It returns false if there is no time in the low
+// or threshold zones.
+//
+// A distribution is polarized when low > high > threshold and the index
+// is above 2; see Polarized.
+func (z ZoneDurations) PolarizationIndex() (float64, bool) {
+	total := float64(z.Low() + z.Threshold() + z.High())
+	if total == 0 || z.Low() == 0 || z.Threshold() == 0 {
+		return 0, false
+	}
+	low, threshold, high := float64(z.Low())/total, float64(z.Threshold())/total, float64(z.High())/total
+	if high == 0 {
+		high = 0.01
+	}
+	return math.Log10(low / threshold * high * 100), true
+}
+
+// Polarized reports whether the distribution of z is polarized.
+func (z ZoneDurations) Polarized() bool {
+	pi, ok := z.PolarizationIndex()
+	return ok && z.Low() > z.High() && z.High() > z.Threshold() && pi > 2
+}
+
+// TRIMP returns the Edwards training impulse of z: the sum of the minutes
+// in each of zones 1 to 5 weighted by the zone number.
+func (z ZoneDurations) TRIMP() float64 {
+	var trimp float64
+	for i := 1; i <= 5; i++ {
+		trimp += z[i].Minutes() * float64(i)
+	}
+	return trimp
+}
+
+// zoneDurations returns the zone durations of w.
+func zoneDurations(w *whoop.Workout) ZoneDurations {
+	zd := w.Score.ZoneDuration
+	return ZoneDurations{
+		millis(zd.ZoneZeroMilli), millis(zd.ZoneOneMilli), millis(zd.ZoneTwoMilli),
+		millis(zd.ZoneThreeMilli), millis(zd.ZoneFourMilli), millis(zd.ZoneFiveMilli),
+	}
+}
+
+// ZoneReport is the heart rate zone distribution of a set of workouts.
+type ZoneReport struct {
+	Sport    string // Name of the sport, if the report is for a sport.
+	Workouts int
+	Zones    ZoneDurations
+
+	PolarizationIndex float64 // See ZoneDurations.PolarizationIndex.
+	HasPolarization   bool    // False if the index is undefined.
+	Polarized         bool
+	TRIMP             float64 // Edwards training impulse.
+}
+
+func (r *ZoneReport) add(z ZoneDurations) {
+	r.Workouts++
+	for i := range z {
+		r.Zones[i] += z[i]
+	}
+}
+
+func (r *ZoneReport) finish() {
+	r.PolarizationIndex, r.HasPolarization = r.Zones.PolarizationIndex()
+	r.Polarized = r.Zones.Polarized()
+	r.TRIMP = r.Zones.TRIMP()
+}
+
+// ZoneWeek is the heart rate zone distribution of a week, starting on
+// Monday, with its breakdown by sport.
+type ZoneWeek struct {
+	Start time.Time
+	ZoneReport
+	Sports []ZoneReport // Sorted by decreasing total time.
+}
+
+// ZoneSummary is the heart rate zone distribution of workouts by week and
+// by sport.
+type ZoneSummary struct {
+	Total  ZoneReport
+	Weeks  []ZoneWeek   // In chronological order.
+	Sports []ZoneReport // Sorted by decreasing total time.
+}
+
+// ZoneDistribution aggregates the zone durations of the scored workouts
+// by week and by sport. The week of a workout is the week of the local
+// date on which it starts.
+func ZoneDistribution(workouts []whoop.Workout) ZoneSummary {
+	var summary ZoneSummary
+	weeks := map[time.Time]*ZoneWeek{}
+	weekSports := map[time.Time]map[string]*ZoneReport{}
+	sports := map[string]*ZoneReport{}
+	for i := range workouts {
+		w := &workouts[i]
+		if !w.Scored() || w.Start == nil {
+			continue
+		}
+		z := zoneDurations(w)
+		sport := w.Sport()
+		start := WeekStart(Date(*w.Start, w.TimezoneOffset))
+
+		summary.Total.add(z)
+		week, ok := weeks[start]
+		if !ok {
+			week = &ZoneWeek{Start: start}
+			weeks[start] = week
+			weekSports[start] = map[string]*ZoneReport{}
+		}
+		week.add(z)
+		sportReport(weekSports[start], sport).add(z)
+		sportReport(sports, sport).add(z)
+	}
+
+	summary.Total.finish()
+	for start, week := range weeks {
+		week.finish()
+		week.Sports = sortedSports(weekSports[start])
+		summary.Weeks = append(summary.Weeks, *week)
+	}
+	sort.Slice(summary.Weeks, func(i, j int) bool { return summary.Weeks[i].Start.Before(summary.Weeks[j].Start) })
+	summary.Sports = sortedSports(sports)
+	return summary
+}
+
+func sportReport(reports map[string]*ZoneReport, sport string) *ZoneReport {
+	r, ok := reports[sport]
+	if !ok {
+		r = &ZoneReport{Sport: sport}
+		reports[sport] = r
+	}
+	return r
+}
+
+func sortedSports(reports map[string]*ZoneReport) []ZoneReport {
+	sorted := make([]ZoneReport, 0, len(reports))
+	for _, r := range reports {
+		r.finish()
+		sorted = append(sorted, *r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		ti, tj := sorted[i].Zones.Total(), sorted[j].Zones.Total()
+		if ti != tj {
+			return ti > tj
+		}
+		return sorted[i].Sport < sorted[j].Sport
+	})
+	return sorted
+}
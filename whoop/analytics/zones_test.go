@@ -0,0 +1,75 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func zoneWorkout(d int, sport string, minutes [6]int) whoop.Workout {
+	w := workout(d, 10, 0)
+	w.SportName = whooptest.Ptr(sport)
+	ms := func(m int) int { return m * 60000 }
+	z := &w.Score.ZoneDuration
+	z.ZoneZeroMilli, z.ZoneOneMilli, z.ZoneTwoMilli = ms(minutes[0]), ms(minutes[1]), ms(minutes[2])
+	z.ZoneThreeMilli, z.ZoneFourMilli, z.ZoneFiveMilli = ms(minutes[3]), ms(minutes[4]), ms(minutes[5])
+	return w
+}
+
+func TestZoneDurations(t *testing.T) {
+	z := ZoneDurations{5 * time.Minute, 40 * time.Minute, 30 * time.Minute, 5 * time.Minute, 15 * time.Minute, 5 * time.Minute}
+	if z.Low() != 70*time.Minute || z.Threshold() != 5*time.Minute || z.High() != 20*time.Minute || z.Total() != 100*time.Minute {
+		t.Errorf("ZoneDurations: got low %v, threshold %v, high %v, total %v", z.Low(), z.Threshold(), z.High(), z.Total())
+	}
+	// Fractions of 70, 5 and 20 out of 95 minutes.
+	want := math.Log10((70.0 / 5) * (20.0 / 95) * 100)
+	if pi, ok := z.PolarizationIndex(); !ok || math.Abs(pi-want) > 1e-9 {
+		t.Errorf("PolarizationIndex(): got %v, %v, want %v", pi, ok, want)
+	}
+	if !z.Polarized() {
+		t.Error("Polarized(): expected polarized distribution")
+	}
+	if got := z.TRIMP(); got != 40+60+15+60+25 {
+		t.Errorf("TRIMP(): got %v, want 200", got)
+	}
+
+	threshold := ZoneDurations{0, 20 * time.Minute, 10 * time.Minute, 40 * time.Minute, 10 * time.Minute, 0}
+	if threshold.Polarized() {
+		t.Error("Polarized(): expected threshold distribution not to be polarized")
+	}
+	if _, ok := (ZoneDurations{0, 30 * time.Minute}).PolarizationIndex(); ok {
+		t.Error("PolarizationIndex(): expected no index without threshold time")
+	}
+}
+
+func TestZoneDistribution(t *testing.T) {
+	unscored := zoneWorkout(22, "Running", [6]int{0, 100})
+	unscored.ScoreState = whooptest.Ptr("PENDING_SCORE")
+	s := ZoneDistribution([]whoop.Workout{
+		zoneWorkout(28, "Running", [6]int{0, 10, 20}),
+		zoneWorkout(21, "Running", [6]int{0, 30, 10, 5}),
+		zoneWorkout(22, "Cycling", [6]int{0, 0, 0, 10, 20, 10}),
+		zoneWorkout(23, "Running", [6]int{5, 10}),
+		unscored,
+	})
+
+	if s.Total.Workouts != 4 || s.Total.Zones.Total() != 130*time.Minute {
+		t.Errorf("ZoneDistribution(): got total %+v", s.Total)
+	}
+	if len(s.Weeks) != 2 || !s.Weeks[0].Start.Equal(day(21)) || !s.Weeks[1].Start.Equal(day(28)) {
+		t.Fatalf("ZoneDistribution(): got weeks %+v", s.Weeks)
+	}
+	week := s.Weeks[0]
+	if week.Workouts != 3 || len(week.Sports) != 2 || week.Sports[0].Sport != "Running" || week.Sports[0].Zones.Total() != 60*time.Minute {
+		t.Errorf("ZoneDistribution(): got first week %+v", week)
+	}
+	if week.TRIMP != 30+20+15+30+80+50+10 {
+		t.Errorf("ZoneDistribution(): got first week TRIMP %v", week.TRIMP)
+	}
+	if len(s.Sports) != 2 || s.Sports[0].Sport != "Running" || s.Sports[0].Workouts != 3 || s.Sports[1].Sport != "Cycling" {
+		t.Errorf("ZoneDistribution(): got sports %+v", s.Sports)
+	}
+}
@@ -0,0 +1,170 @@
+package whoop
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPendingTTL = time.Minute
+	defaultScoredTTL  = 24 * time.Hour
+)
+
+// Cache is a storage backend for cached API responses.
+// Implementations must be safe for concurrent use.
+//
+// Entries are keyed by endpoint and query, so a Cache must not be shared
+// between clients authenticated as different users.
+type Cache interface {
+	// Get returns the entry stored for key, if any.
+	Get(key string) (CacheEntry, bool)
+	// Set stores entry for key, replacing any previous entry.
+	Set(key string, entry CacheEntry)
+}
+
+// CacheEntry is a cached API response.
+type CacheEntry struct {
+	Body    []byte    // Raw JSON response body.
+	ETag    string    // ETag returned by the API, if any.
+	Expires time.Time // Time after which the entry must be revalidated.
+}
+
+// CacheTTL configures how long cached responses are considered fresh.
+// Zero values are replaced by sensible defaults.
+type CacheTTL struct {
+	// Pending is used for records that may still change, such as the
+	// current unfinished cycle or records with a PENDING_SCORE state, for
+	// pages of records listed without an End bound in the past, such as
+	// the latest records, and for responses that are not scored records,
+	// like the user profile. Defaults to one minute.
+	Pending time.Duration
+
+	// Scored is used for scored historical records: single records, and
+	// pages of records listed with an End bound in the past, to which no
+	// new records can be added. Defaults to 24 hours.
+	Scored time.Duration
+}
+
+// WithCache sets the cache used by the client for GET requests. Fresh
+// entries are served without making a network request, while stale entries
+// with an ETag are revalidated using If-None-Match. It returns the client
+// to allow chaining with NewClient. A nil cache disables caching.
+func (c *Client) WithCache(cache Cache, ttl CacheTTL) *Client {
+	if ttl.Pending <= 0 {
+		ttl.Pending = defaultPendingTTL
+	}
+	if ttl.Scored <= 0 {
+		ttl.Scored = defaultScoredTTL
+	}
+	c.cache = cache
+	c.cacheTTL = ttl
+	return c
+}
+
+// settler is implemented by API responses that know whether they hold
+// final, scored data which is not expected to change anymore.
+type settler interface {
+	settled() bool
+}
+
+// page is implemented by the responses of list endpoints.
+type page interface {
+	page()
+}
+
+// cacheKey returns the cache key for req.
+func cacheKey(req *http.Request) string {
+	return req.URL.RequestURI()
+}
+
+// cacheLookup returns the cached entry for req, if caching is enabled.
+func (c *Client) cacheLookup(req *http.Request) (CacheEntry, bool) {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return CacheEntry{}, false
+	}
+	return c.cache.Get(cacheKey(req))
+}
+
+// cacheStore stores body as the cached response for req. The entry TTL
+// depends on whether v, the decoded body, holds final scored data. A page
+// of records is only final if its query ends in the past, since new
+// records are added to open-ended queries such as the latest records.
+func (c *Client) cacheStore(req *http.Request, etag string, body []byte, v any) {
+	if c.cache == nil || req.Method != http.MethodGet {
+		return
+	}
+	ttl := c.cacheTTL.Pending
+	if s, ok := v.(settler); ok && s.settled() {
+		if _, ok := v.(page); !ok || endsBefore(req, now()) {
+			ttl = c.cacheTTL.Scored
+		}
+	}
+	c.cache.Set(cacheKey(req), CacheEntry{Body: body, ETag: etag, Expires: now().Add(ttl)})
+}
+
+// endsBefore reports whether the query of req has an end bound before t.
+func endsBefore(req *http.Request, t time.Time) bool {
+	end, err := time.Parse(time.RFC3339, req.URL.Query().Get("end"))
+	return err == nil && end.Before(t)
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used
+// entry once it holds its maximum number of entries.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewLRUCache returns a new LRUCache holding at most size entries.
+// If size is less than 1, a size of 1 is used.
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+	return &LRUCache{size: size, ll: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// Get returns the entry stored for key, if any.
+func (l *LRUCache) Get(key string) (CacheEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	l.ll.MoveToFront(e)
+	return e.Value.(*lruItem).entry, true
+}
+
+// Set stores entry for key, evicting the least recently used entry if needed.
+func (l *LRUCache) Set(key string, entry CacheEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e, ok := l.entries[key]; ok {
+		e.Value.(*lruItem).entry = entry
+		l.ll.MoveToFront(e)
+		return
+	}
+	l.entries[key] = l.ll.PushFront(&lruItem{key: key, entry: entry})
+	if l.ll.Len() > l.size {
+		oldest := l.ll.Back()
+		l.ll.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len()
+}
@@ -0,0 +1,166 @@
+package whoop
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", CacheEntry{ETag: "a"})
+	c.Set("b", CacheEntry{ETag: "b"})
+	c.Get("a")
+	c.Set("c", CacheEntry{ETag: "c"})
+
+	if got, want := c.Len(), 2; got != want {
+		t.Errorf("LRUCache.Len(): got %v, want %v", got, want)
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("LRUCache.Get(b): expected least recently used entry to be evicted")
+	}
+	if e, ok := c.Get("a"); !ok || e.ETag != "a" {
+		t.Errorf("LRUCache.Get(a): got %v %v, want entry a", e, ok)
+	}
+	c.Set("a", CacheEntry{ETag: "a2"})
+	if e, _ := c.Get("a"); e.ETag != "a2" {
+		t.Errorf("LRUCache.Get(a): got ETag %v, want a2", e.ETag)
+	}
+}
+
+func TestClient_cache(t *testing.T) {
+	date := now()
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time {
+		return date
+	}
+
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.WithCache(NewLRUCache(10), CacheTTL{})
+
+	calls := 0
+	mux.HandleFunc("/"+apiVersion+cycleEndpoint+"/", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		end := `"2022-11-27T08:17:45.687Z"`
+		if r.URL.Path == "/"+apiVersion+cycleEndpoint+"/2" {
+			end = "null"
+		}
+		fmt.Fprintf(w, `{"id": 1, "end": %v, "score_state": "SCORED", "score": {"strain": 4.5}}`, end)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		cycle, err := client.Cycle.GetOne(ctx, 1)
+		if err != nil {
+			t.Fatalf("Cycle.GetOne(): expected nil error, got %#v", err)
+		}
+		if cycle.Score.Strain != 4.5 {
+			t.Errorf("Cycle.GetOne(): expected strain 4.5, got %v", cycle.Score.Strain)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Cycle.GetOne(): expected 1 network request, got %v", calls)
+	}
+
+	// The unfinished cycle expires after the pending TTL, the scored one doesn't.
+	client.Cycle.GetOne(ctx, 2)
+	now = func() time.Time {
+		return date.Add(defaultPendingTTL + time.Second)
+	}
+	client.Cycle.GetOne(ctx, 1)
+	client.Cycle.GetOne(ctx, 2)
+	if calls != 3 {
+		t.Errorf("Cycle.GetOne(): expected 3 network requests, got %v", calls)
+	}
+}
+
+func TestClient_cache_etag(t *testing.T) {
+	date := now()
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time {
+		return date
+	}
+
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.WithCache(NewLRUCache(10), CacheTTL{Pending: time.Second})
+
+	calls := 0
+	mux.HandleFunc("/"+apiVersion+userEndpoint+"/profile/basic", func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"user_id": 10}`)
+	})
+
+	ctx := context.Background()
+	if _, err := client.User.GetProfile(ctx); err != nil {
+		t.Fatalf("User.GetProfile(): expected nil error, got %#v", err)
+	}
+	now = func() time.Time {
+		return date.Add(time.Minute)
+	}
+	profile, err := client.User.GetProfile(ctx)
+	if err != nil {
+		t.Fatalf("User.GetProfile(): expected nil error, got %#v", err)
+	}
+	if profile.ID != 10 {
+		t.Errorf("User.GetProfile(): expected ID 10 from cache, got %v", profile.ID)
+	}
+	if calls != 2 {
+		t.Errorf("User.GetProfile(): expected 2 network requests, got %v", calls)
+	}
+	if _, err := client.User.GetProfile(ctx); err != nil || calls != 2 {
+		t.Errorf("User.GetProfile(): expected revalidated entry to be fresh, got %v requests", calls)
+	}
+}
+
+func TestClient_cache_list(t *testing.T) {
+	date := time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC)
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time {
+		return date
+	}
+
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.WithCache(NewLRUCache(10), CacheTTL{})
+
+	calls := 0
+	mux.HandleFunc("/"+apiVersion+recoveryEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"records": [{"cycle_id": 1, "score_state": "SCORED", "score": {"recovery_score": 80}}]}`)
+	})
+
+	ctx := context.Background()
+	latest := &RequestParams{Limit: 1}
+	bounded := &RequestParams{End: date.Add(-24 * time.Hour)}
+	for _, params := range []*RequestParams{latest, bounded, latest, bounded} {
+		if _, err := client.Recovery.ListAll(ctx, params); err != nil {
+			t.Fatalf("Recovery.ListAll(): expected nil error, got %#v", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("Recovery.ListAll(): expected 2 network requests, got %v", calls)
+	}
+
+	// The latest records expire after the pending TTL, the page ending in
+	// the past doesn't.
+	now = func() time.Time {
+		return date.Add(defaultPendingTTL + time.Second)
+	}
+	client.Recovery.ListAll(ctx, latest)
+	client.Recovery.ListAll(ctx, bounded)
+	if calls != 3 {
+		t.Errorf("Recovery.ListAll(): expected 3 network requests, got %v", calls)
+	}
+}
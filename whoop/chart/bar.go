@@ -0,0 +1,72 @@
+package chart
+
+import (
+	"io"
+	"math"
+)
+
+// Segment is a part of a stacked bar.
+type Segment struct {
+	Name  string // Name of the segment in the legend, if any.
+	Value float64
+	Color string
+}
+
+// Bar is a bar made of stacked segments. A bar without segments is an
+// empty slot.
+type Bar struct {
+	Label    string
+	Segments []Segment
+}
+
+// maxBarLabels is the maximum number of labels below bars. Only every
+// other label, or fewer, is drawn when there are more bars.
+const maxBarLabels = 16
+
+// Bars renders bars as a bar chart. Segments of a bar are stacked from
+// the bottom up, and named segments are listed in a legend in the order
+// they first appear. Negative values are drawn as zero.
+func Bars(w io.Writer, opts Options, bars []Bar) error {
+	yMax := 0.0
+	var names, colors []string
+	seen := map[string]bool{}
+	for _, b := range bars {
+		total := 0.0
+		for _, s := range b.Segments {
+			total += math.Max(s.Value, 0)
+			if s.Name != "" && !seen[s.Name] {
+				seen[s.Name] = true
+				names, colors = append(names, s.Name), append(colors, s.Color)
+			}
+		}
+		yMax = math.Max(yMax, total)
+	}
+
+	c := newCanvas(w, opts, 0, yMax, len(names) > 0)
+	c.yAxis()
+	if len(bars) > 0 {
+		slot := c.plotW / float64(len(bars))
+		width := slot * 0.7
+		every := (len(bars) + maxBarLabels - 1) / maxBarLabels
+		for i, b := range bars {
+			x := c.left + float64(i)*slot
+			if i%every == 0 {
+				c.xTick(x+slot/2, b.Label)
+			}
+			base := 0.0
+			for _, s := range b.Segments {
+				v := math.Max(s.Value, 0)
+				lo, hi := math.Max(base, c.yMin), math.Min(base+v, c.yMax)
+				base += v
+				if hi <= lo {
+					continue
+				}
+				c.printf(`<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`, num(x+(slot-width)/2), num(c.y(hi)), num(width), num(c.y(lo)-c.y(hi)), escape(s.Color))
+			}
+		}
+	}
+	if len(names) > 0 {
+		c.legend(names, colors)
+	}
+	return c.close()
+}
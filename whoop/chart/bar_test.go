@@ -0,0 +1,48 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBars(t *testing.T) {
+	var b strings.Builder
+	err := Bars(&b, Options{Height: 234}, []Bar{
+		{Label: "Mon", Segments: []Segment{{"Light", 4, "#aaa"}, {"REM", 1, "#bbb"}}},
+		{Label: "Tue"},
+		{Label: "<Wed>", Segments: []Segment{{"Light", 2, "#aaa"}, {"Awake", -1, "#ccc"}}},
+	})
+	if err != nil {
+		t.Fatalf("Bars(): %v", err)
+	}
+	got := b.String()
+	// The plot is 160 pixels high from y=32, with a scale from 0 to 5.
+	for _, want := range []string{
+		`<rect x="76.8" y="64.0" width="134.4" height="128.0" fill="#aaa"/>`,
+		`<rect x="76.8" y="32.0" width="134.4" height="32.0" fill="#bbb"/>`,
+		`<rect x="460.8" y="128.0" width="134.4" height="64.0" fill="#aaa"/>`,
+		`>&lt;Wed&gt;</text>`,
+		`<text x="62.0" y="225.0">Light</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Bars(): got %s, want it to contain %s", got, want)
+		}
+	}
+	if n := strings.Count(got, "<rect "); n != 3+3 {
+		t.Errorf("Bars(): got %d rects, want 3 segments and 3 legend entries", n)
+	}
+}
+
+func TestBars_labels(t *testing.T) {
+	bars := make([]Bar, 31)
+	for i := range bars {
+		bars[i].Label = "d"
+	}
+	var b strings.Builder
+	if err := Bars(&b, Options{}, bars); err != nil {
+		t.Fatalf("Bars(): %v", err)
+	}
+	if n := strings.Count(b.String(), ">d</text>"); n != 16 {
+		t.Errorf("Bars(): got %d labels, want 16", n)
+	}
+}
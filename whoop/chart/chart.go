@@ -0,0 +1,229 @@
+// Package chart renders WHOOP metrics as SVG charts, for embedding into
+// emails and reports.
+//
+// Charts are self-contained SVG documents without scripts or external
+// resources. Line, Scatter and Bars render generic data; MetricLine,
+// SleepStages, StrainRecovery and Zones render WHOOP records and the
+// results of package analytics.
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/ferueda/go-whoop/whoop/analytics"
+)
+
+// Colors used by the charts.
+const (
+	RecoveryColor = "#16ec06"
+	HRVColor      = "#8b5cf6"
+	RHRColor      = "#ff6b6b"
+	StrainColor   = "#0093e7"
+	LightColor    = "#a3b8cc"
+	SWSColor      = "#3b5b92"
+	REMColor      = "#c084fc"
+	AwakeColor    = "#e5e7eb"
+)
+
+// ZoneColors are the colors of heart rate zones 0 to 5.
+var ZoneColors = [6]string{"#d1d5db", "#93c5fd", "#34d399", "#facc15", "#fb923c", "#ef4444"}
+
+// BandColor returns the color of a recovery band, as shown in the WHOOP
+// app.
+func BandColor(b analytics.Band) string {
+	switch b {
+	case analytics.Green:
+		return "#16ec06"
+	case analytics.Yellow:
+		return "#ffde00"
+	default:
+		return "#ff0026"
+	}
+}
+
+// Options configures a chart.
+type Options struct {
+	Title  string
+	Width  int // Width in pixels. Defaults to 640.
+	Height int // Height in pixels. Defaults to 240.
+
+	// YMin and YMax bound the vertical axis. If both are zero, the axis
+	// spans the data.
+	YMin, YMax float64
+
+	XLabel, YLabel string // Axis labels, if any.
+}
+
+// Margins around the plot, in pixels.
+const (
+	marginTop    = 32
+	marginRight  = 16
+	marginBottom = 24 // Labels of the horizontal axis.
+	marginLeft   = 48
+	rowHeight    = 18 // Label of the horizontal axis or legend.
+	fontSize     = 11
+)
+
+// canvas is an SVG document being written, with the plot area and the
+// scales of its axes.
+type canvas struct {
+	w                 *bufio.Writer
+	width, height     int
+	left, top         float64 // Top left corner of the plot area.
+	plotW, plotH      float64
+	legendY           float64
+	xMin, xMax        float64
+	yMin, yMax, yStep float64
+}
+
+// newCanvas starts an SVG document for opts and returns its canvas, with
+// a vertical scale covering yMin to yMax and room for a legend if legend
+// is set.
+func newCanvas(w io.Writer, opts Options, yMin, yMax float64, legend bool) *canvas {
+	c := &canvas{w: bufio.NewWriter(w), width: opts.Width, height: opts.Height}
+	if c.width <= 0 {
+		c.width = 640
+	}
+	if c.height <= 0 {
+		c.height = 240
+	}
+	if opts.YMin != 0 || opts.YMax != 0 {
+		yMin, yMax = opts.YMin, opts.YMax
+	}
+	c.yMin, c.yMax, c.yStep = niceRange(yMin, yMax)
+
+	bottom := float64(marginBottom)
+	if opts.XLabel != "" {
+		bottom += rowHeight
+	}
+	if legend {
+		bottom += rowHeight
+	}
+	left := float64(marginLeft)
+	if opts.YLabel != "" {
+		left += fontSize + 4
+	}
+	c.left, c.top = left, marginTop
+	c.plotW = math.Max(float64(c.width)-left-marginRight, 1)
+	c.plotH = math.Max(float64(c.height)-marginTop-bottom, 1)
+
+	c.printf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %[1]d %[2]d" font-family="Helvetica, Arial, sans-serif" font-size="%d" role="img">`, c.width, c.height, fontSize)
+	if opts.Title != "" {
+		c.printf(`<title>%s</title>`, escape(opts.Title))
+		c.printf(`<text x="%s" y="20" font-size="14" font-weight="bold">%s</text>`, num(c.left), escape(opts.Title))
+	}
+	c.legendY = c.top + c.plotH + marginBottom
+	if opts.XLabel != "" {
+		c.printf(`<text x="%s" y="%s" text-anchor="middle" fill="#666">%s</text>`, num(c.left+c.plotW/2), num(c.legendY+fontSize), escape(opts.XLabel))
+		c.legendY += rowHeight
+	}
+	if opts.YLabel != "" {
+		c.printf(`<text x="%d" y="%s" text-anchor="middle" fill="#666" transform="rotate(-90 %[1]d %[2]s)">%s</text>`, fontSize, num(c.top+c.plotH/2), escape(opts.YLabel))
+	}
+	return c
+}
+
+// yAxis draws the horizontal grid lines and the labels of the vertical
+// axis.
+func (c *canvas) yAxis() {
+	for v := c.yMin; v <= c.yMax+c.yStep/2; v += c.yStep {
+		y := c.y(v)
+		c.printf(`<line x1="%s" y1="%s" x2="%s" y2="%[2]s" stroke="#eee"/>`, num(c.left), num(y), num(c.left+c.plotW))
+		c.printf(`<text x="%s" y="%s" text-anchor="end" fill="#666">%s</text>`, num(c.left-6), num(y+4), tick(v, c.yStep))
+	}
+	base := c.y(math.Max(c.yMin, math.Min(0, c.yMax)))
+	c.printf(`<line x1="%s" y1="%s" x2="%s" y2="%[2]s" stroke="#999"/>`, num(c.left), num(base), num(c.left+c.plotW))
+}
+
+// xTick draws a label of the horizontal axis at x.
+func (c *canvas) xTick(x float64, label string) {
+	c.printf(`<text x="%s" y="%s" text-anchor="middle" fill="#666">%s</text>`, num(x), num(c.top+c.plotH+16), escape(label))
+}
+
+// legend draws entries of names and colors below the plot.
+func (c *canvas) legend(names, colors []string) {
+	x, y := c.left, c.legendY
+	for i, name := range names {
+		c.printf(`<rect x="%s" y="%s" width="10" height="10" fill="%s"/>`, num(x), num(y), escape(colors[i]))
+		c.printf(`<text x="%s" y="%s">%s</text>`, num(x+14), num(y+9), escape(name))
+		x += 14 + float64(len(name))*fontSize*0.6 + 16
+	}
+}
+
+// y returns the vertical position of v.
+func (c *canvas) y(v float64) float64 {
+	return c.top + c.plotH - (v-c.yMin)/(c.yMax-c.yMin)*c.plotH
+}
+
+// x returns the horizontal position of v, on a scale from xMin to xMax.
+func (c *canvas) x(v float64) float64 {
+	if c.xMax == c.xMin {
+		return c.left + c.plotW/2
+	}
+	return c.left + (v-c.xMin)/(c.xMax-c.xMin)*c.plotW
+}
+
+func (c *canvas) printf(format string, args ...any) {
+	fmt.Fprintf(c.w, format, args...)
+}
+
+// close ends the document and flushes it.
+func (c *canvas) close() error {
+	c.printf("</svg>")
+	return c.w.Flush()
+}
+
+// niceRange extends min and max to round numbers and returns them with
+// the step between ticks, aiming at about five ticks.
+func niceRange(min, max float64) (float64, float64, float64) {
+	if math.IsInf(min, 0) || math.IsNaN(min) || math.IsInf(max, 0) || math.IsNaN(max) {
+		min, max = 0, 1
+	}
+	if min == max {
+		if min == 0 {
+			max = 1
+		} else {
+			min, max = min-math.Abs(min)/2, max+math.Abs(max)/2
+		}
+	}
+	step := niceStep((max - min) / 5)
+	return math.Floor(min/step) * step, math.Ceil(max/step) * step, step
+}
+
+// niceStep returns the smallest of 1, 2 or 5 times a power of ten which
+// is at least v.
+func niceStep(v float64) float64 {
+	p := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if m*p >= v*(1-1e-9) {
+			return m * p
+		}
+	}
+	return 10 * p
+}
+
+// tick formats a tick value with as many decimals as step.
+func tick(v, step float64) string {
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step) - 1e-9))
+	}
+	if math.Abs(v) < step/2 {
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', decimals, 64)
+}
+
+// num formats a coordinate.
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+func escape(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,85 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop/analytics"
+)
+
+func TestNiceRange(t *testing.T) {
+	tests := []struct {
+		min, max            float64
+		wantMin, wantMax, s float64
+	}{
+		{0, 100, 0, 100, 20},
+		{23, 87, 20, 100, 20},
+		{0, 21, 0, 25, 5},
+		{45.2, 61.9, 45, 65, 5},
+		{0, 0, 0, 1, 0.2},
+		{50, 50, 20, 80, 10},
+	}
+	for _, tt := range tests {
+		min, max, step := niceRange(tt.min, tt.max)
+		if min != tt.wantMin || max != tt.wantMax || step != tt.s {
+			t.Errorf("niceRange(%v, %v): got %v, %v, %v, want %v, %v, %v", tt.min, tt.max, min, max, step, tt.wantMin, tt.wantMax, tt.s)
+		}
+	}
+}
+
+func TestTick(t *testing.T) {
+	tests := []struct {
+		v, step float64
+		want    string
+	}{
+		{20, 20, "20"},
+		{0.6000000000000001, 0.2, "0.6"},
+		{1e-17, 0.5, "0.0"},
+		{0.25, 0.05, "0.25"},
+	}
+	for _, tt := range tests {
+		if got := tick(tt.v, tt.step); got != tt.want {
+			t.Errorf("tick(%v, %v): got %q, want %q", tt.v, tt.step, got, tt.want)
+		}
+	}
+}
+
+func TestBandColor(t *testing.T) {
+	if got := BandColor(analytics.Green); got != "#16ec06" {
+		t.Errorf("BandColor(): got %q, want %q", got, "#16ec06")
+	}
+	if got := BandColor(analytics.Red); got != "#ff0026" {
+		t.Errorf("BandColor(): got %q, want %q", got, "#ff0026")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	var b strings.Builder
+	if err := Bars(&b, Options{Title: "Q&A <1>", Width: 300, Height: 150, XLabel: "x", YLabel: "y"}, nil); err != nil {
+		t.Fatalf("Bars(): %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="300" height="150" viewBox="0 0 300 150" `,
+		`<title>Q&amp;A &lt;1&gt;</title>`,
+		`>x</text>`,
+		`transform="rotate(-90 11 `,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Bars(): got %s, want it to contain %s", got, want)
+		}
+	}
+}
+
+func TestColorsEscaped(t *testing.T) {
+	color := `red"/><script>alert(1)</script><x a="`
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	Bars(&b, Options{}, []Bar{{Label: "a", Segments: []Segment{{Name: "x", Value: 1, Color: color}}}})
+	Line(&b, Options{}, TimeSeries{Color: color, Points: []TimePoint{{now, 1}}}, TimeSeries{Color: color, Points: []TimePoint{{now, 1}, {now.Add(time.Hour), 2}}})
+	Scatter(&b, Options{}, []Point{{1, 1, color}})
+	if got := b.String(); strings.Contains(got, "<script>") {
+		t.Errorf("charts: got unescaped color in %s", got)
+	}
+}
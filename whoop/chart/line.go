@@ -0,0 +1,101 @@
+package chart
+
+import (
+	"io"
+	"math"
+	"strings"
+	"time"
+)
+
+// TimePoint is a value at a point in time. A NaN value is a gap in the
+// line.
+type TimePoint struct {
+	Time  time.Time
+	Value float64
+}
+
+// TimeSeries is a named series of values over time.
+type TimeSeries struct {
+	Name   string
+	Color  string
+	Points []TimePoint // In chronological order.
+}
+
+// xTicks is the maximum number of labels of the horizontal axis.
+const xTicks = 6
+
+// Line renders series as a line chart over time. Points without a
+// neighbour on either side are drawn as dots. A legend is drawn if there
+// are several series.
+func Line(w io.Writer, opts Options, series ...TimeSeries) error {
+	yMin, yMax := math.Inf(1), math.Inf(-1)
+	var tMin, tMax time.Time
+	for _, s := range series {
+		for _, p := range s.Points {
+			if math.IsNaN(p.Value) {
+				continue
+			}
+			yMin, yMax = math.Min(yMin, p.Value), math.Max(yMax, p.Value)
+			if tMin.IsZero() || p.Time.Before(tMin) {
+				tMin = p.Time
+			}
+			if tMax.IsZero() || p.Time.After(tMax) {
+				tMax = p.Time
+			}
+		}
+	}
+
+	c := newCanvas(w, opts, yMin, yMax, len(series) > 1)
+	c.xMin, c.xMax = unix(tMin), unix(tMax)
+	c.yAxis()
+	if !tMin.IsZero() {
+		labels := xTicks
+		if tMax.Equal(tMin) {
+			labels = 1
+		}
+		for i := 0; i < labels; i++ {
+			t := tMin
+			if labels > 1 {
+				t = tMin.Add(time.Duration(float64(tMax.Sub(tMin)) * float64(i) / float64(labels-1)))
+			}
+			c.xTick(c.x(unix(t)), t.Format("Jan 2"))
+		}
+	}
+
+	var names, colors []string
+	for _, s := range series {
+		names, colors = append(names, s.Name), append(colors, s.Color)
+		var segment []TimePoint
+		draw := func() {
+			switch len(segment) {
+			case 0:
+			case 1:
+				c.printf(`<circle cx="%s" cy="%s" r="2.5" fill="%s"/>`, num(c.x(unix(segment[0].Time))), num(c.y(segment[0].Value)), escape(s.Color))
+			default:
+				points := make([]string, len(segment))
+				for i, p := range segment {
+					points[i] = num(c.x(unix(p.Time))) + "," + num(c.y(p.Value))
+				}
+				c.printf(`<polyline points="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round"/>`, strings.Join(points, " "), escape(s.Color))
+			}
+			segment = nil
+		}
+		for _, p := range s.Points {
+			if math.IsNaN(p.Value) {
+				draw()
+				continue
+			}
+			segment = append(segment, p)
+		}
+		draw()
+	}
+	if len(series) > 1 {
+		c.legend(names, colors)
+	}
+	return c.close()
+}
+
+// unix returns t in seconds since the Unix epoch.
+func unix(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
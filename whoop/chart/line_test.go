@@ -0,0 +1,59 @@
+package chart
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLine(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2022, 11, d, 0, 0, 0, 0, time.UTC) }
+	var b strings.Builder
+	err := Line(&b, Options{YMin: 0, YMax: 100, Height: 216}, TimeSeries{
+		Name:  "Recovery",
+		Color: "#0f0",
+		Points: []TimePoint{
+			{day(1), 50}, {day(2), 100}, {day(3), math.NaN()}, {day(4), 0}, {day(5), math.NaN()}, {day(6), 25}, {day(11), 75},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Line(): %v", err)
+	}
+	got := b.String()
+	// The plot is 576 pixels wide from x=48 over 10 days, and 160 pixels
+	// high from y=32.
+	for _, want := range []string{
+		`<polyline points="48.0,112.0 105.6,32.0" fill="none" stroke="#0f0"`,
+		`<circle cx="220.8" cy="192.0" r="2.5" fill="#0f0"/>`,
+		`<polyline points="336.0,152.0 624.0,72.0"`,
+		`>Nov 1</text>`,
+		`>Nov 11</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Line(): got %s, want it to contain %s", got, want)
+		}
+	}
+	if strings.Contains(got, "<rect ") {
+		t.Errorf("Line(): got a legend for a single series in %s", got)
+	}
+}
+
+func TestLine_legend(t *testing.T) {
+	now := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	err := Line(&b, Options{},
+		TimeSeries{Name: "HRV", Color: "#00f", Points: []TimePoint{{now, 40}}},
+		TimeSeries{Name: "RHR", Color: "#f00", Points: []TimePoint{{now, 50}}},
+	)
+	if err != nil {
+		t.Fatalf("Line(): %v", err)
+	}
+	got := b.String()
+	if !strings.Contains(got, ">HRV</text>") || !strings.Contains(got, ">RHR</text>") {
+		t.Errorf("Line(): got %s, want a legend of HRV and RHR", got)
+	}
+	if n := strings.Count(got, ">Nov 1</text>"); n != 1 {
+		t.Errorf("Line(): got %d labels for a single time, want 1", n)
+	}
+}
@@ -0,0 +1,34 @@
+package chart
+
+import (
+	"io"
+	"math"
+)
+
+// Point is a point of a scatter plot.
+type Point struct {
+	X, Y  float64
+	Color string
+}
+
+// Scatter renders points as a scatter plot.
+func Scatter(w io.Writer, opts Options, points []Point) error {
+	xMin, xMax := math.Inf(1), math.Inf(-1)
+	yMin, yMax := math.Inf(1), math.Inf(-1)
+	for _, p := range points {
+		xMin, xMax = math.Min(xMin, p.X), math.Max(xMax, p.X)
+		yMin, yMax = math.Min(yMin, p.Y), math.Max(yMax, p.Y)
+	}
+
+	c := newCanvas(w, opts, yMin, yMax, false)
+	var step float64
+	c.xMin, c.xMax, step = niceRange(xMin, xMax)
+	c.yAxis()
+	for v := c.xMin; v <= c.xMax+step/2; v += step {
+		c.xTick(c.x(v), tick(v, step))
+	}
+	for _, p := range points {
+		c.printf(`<circle cx="%s" cy="%s" r="4" fill="%s" fill-opacity="0.8"/>`, num(c.x(p.X)), num(c.y(p.Y)), escape(p.Color))
+	}
+	return c.close()
+}
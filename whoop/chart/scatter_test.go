@@ -0,0 +1,27 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScatter(t *testing.T) {
+	var b strings.Builder
+	err := Scatter(&b, Options{YMax: 100, Height: 216}, []Point{{2, 80, "#0f0"}, {18.5, 20, "#f00"}})
+	if err != nil {
+		t.Fatalf("Scatter(): %v", err)
+	}
+	got := b.String()
+	// The plot is 576 pixels wide from x=48 from 0 to 20, and 160 pixels
+	// high from y=32 from 0 to 100.
+	for _, want := range []string{
+		`<circle cx="105.6" cy="64.0" r="4" fill="#0f0" fill-opacity="0.8"/>`,
+		`<circle cx="580.8" cy="160.0" r="4" fill="#f00" fill-opacity="0.8"/>`,
+		`>15</text>`,
+		`>100</text>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Scatter(): got %s, want it to contain %s", got, want)
+		}
+	}
+}
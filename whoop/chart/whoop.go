@@ -0,0 +1,150 @@
+package chart
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/analytics"
+)
+
+// metricStyles are the default titles, axis labels and colors of the
+// metrics of MetricLine.
+var metricStyles = map[analytics.Metric]struct{ title, unit, color string }{
+	analytics.RecoveryScore:    {"Recovery", "%", RecoveryColor},
+	analytics.HRV:              {"Heart rate variability", "ms", HRVColor},
+	analytics.RestingHeartRate: {"Resting heart rate", "bpm", RHRColor},
+	analytics.RespiratoryRate:  {"Respiratory rate", "breaths/min", RHRColor},
+	analytics.SkinTemp:         {"Skin temperature", "°C", RHRColor},
+	analytics.Strain:           {"Strain", "", StrainColor},
+}
+
+// MetricLine renders a daily series, such as the recovery score, HRV or
+// resting heart rate returned by analytics.DailySeries, as a line chart.
+// Missing days are gaps in the line. The title and vertical axis label
+// default to the name and unit of the metric.
+func MetricLine(w io.Writer, opts Options, s analytics.Series) error {
+	style := metricStyles[s.Metric]
+	if opts.Title == "" {
+		opts.Title = style.title
+	}
+	if opts.YLabel == "" {
+		opts.YLabel = style.unit
+	}
+	color := style.color
+	if color == "" {
+		color = StrainColor
+	}
+	ts := TimeSeries{Name: opts.Title, Color: color}
+	for _, d := range s.Days {
+		v := math.NaN()
+		if d.Present {
+			v = d.Value
+		}
+		ts.Points = append(ts.Points, TimePoint{Time: d.Date, Value: v})
+	}
+	return Line(w, opts, ts)
+}
+
+// SleepStages renders the stage summary of each scored night of sleep as
+// a stacked bar of the hours of light, slow wave and REM sleep and of
+// awake time, in chronological order. Naps are skipped.
+func SleepStages(w io.Writer, opts Options, sleeps []whoop.Sleep) error {
+	var nights []*whoop.Sleep
+	for i := range sleeps {
+		s := &sleeps[i]
+		if !s.Nap && s.Start != nil && s.Scored() {
+			nights = append(nights, s)
+		}
+	}
+	sort.Slice(nights, func(i, j int) bool { return nights[i].Start.Before(*nights[j].Start) })
+	if opts.Title == "" {
+		opts.Title = "Sleep stages"
+	}
+	if opts.YLabel == "" {
+		opts.YLabel = "hours"
+	}
+
+	bars := make([]Bar, 0, len(nights))
+	for _, s := range nights {
+		date, _ := analytics.SleepDate(s)
+		st := s.Score.StageSummary
+		bars = append(bars, Bar{Label: date.Format("Jan 2"), Segments: []Segment{
+			{"Light", hours(st.TotalLightSleepTimeMilli), LightColor},
+			{"SWS", hours(st.TotalSlowWaveSleepTimeMilli), SWSColor},
+			{"REM", hours(st.TotalRemSleepTimeMilli), REMColor},
+			{"Awake", hours(st.TotalAwakeTimeMilli), AwakeColor},
+		}})
+	}
+	return Bars(w, opts, bars)
+}
+
+// StrainRecovery renders the strain of each completed cycle against the
+// recovery of the next cycle as a scatter plot, with points colored by
+// recovery band. Cycles are paired by analytics.PairDays. The
+// vertical axis defaults to 0 to 100%.
+func StrainRecovery(w io.Writer, opts Options, cycles []whoop.Cycle, recoveries []whoop.Recovery) error {
+	if opts.Title == "" {
+		opts.Title = "Strain and next-day recovery"
+	}
+	if opts.XLabel == "" {
+		opts.XLabel = "Strain"
+	}
+	if opts.YLabel == "" {
+		opts.YLabel = "Recovery (%)"
+	}
+	if opts.YMin == 0 && opts.YMax == 0 {
+		opts.YMax = 100
+	}
+	var points []Point
+	for _, d := range analytics.PairDays(cycles, recoveries, nil, nil) {
+		points = append(points, Point{
+			X:     d.Features[analytics.FeatureStrain],
+			Y:     d.RecoveryScore,
+			Color: BandColor(analytics.RecoveryBand(d.RecoveryScore)),
+		})
+	}
+	return Scatter(w, opts, points)
+}
+
+// Zones renders the time in each heart rate zone of z as bars, in
+// minutes.
+func Zones(w io.Writer, opts Options, z analytics.ZoneDurations) error {
+	if opts.Title == "" {
+		opts.Title = "Heart rate zones"
+	}
+	if opts.YLabel == "" {
+		opts.YLabel = "minutes"
+	}
+	bars := make([]Bar, len(z))
+	for i, d := range z {
+		bars[i] = Bar{Label: fmt.Sprintf("Zone %d", i), Segments: []Segment{{Value: d.Minutes(), Color: ZoneColors[i]}}}
+	}
+	return Bars(w, opts, bars)
+}
+
+// WeeklyZones renders the zone distribution of each week, as returned by
+// analytics.ZoneDistribution, as stacked bars of the hours in each zone.
+func WeeklyZones(w io.Writer, opts Options, weeks []analytics.ZoneWeek) error {
+	if opts.Title == "" {
+		opts.Title = "Weekly heart rate zones"
+	}
+	if opts.YLabel == "" {
+		opts.YLabel = "hours"
+	}
+	bars := make([]Bar, len(weeks))
+	for i, week := range weeks {
+		bars[i].Label = week.Start.Format("Jan 2")
+		for zone, d := range week.Zones {
+			bars[i].Segments = append(bars[i].Segments, Segment{Name: fmt.Sprintf("Zone %d", zone), Value: d.Hours(), Color: ZoneColors[zone]})
+		}
+	}
+	return Bars(w, opts, bars)
+}
+
+func hours(ms int) float64 {
+	return (time.Duration(ms) * time.Millisecond).Hours()
+}
@@ -0,0 +1,135 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/analytics"
+)
+
+func day(d int) time.Time {
+	return time.Date(2022, 11, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestMetricLine(t *testing.T) {
+	s := analytics.Series{Metric: analytics.HRV, Days: []analytics.Day{
+		{Date: day(1), Value: 40, Present: true},
+		{Date: day(2), Value: 45, Present: true},
+		{Date: day(3)},
+		{Date: day(4), Value: 50, Present: true},
+	}}
+	var b strings.Builder
+	if err := MetricLine(&b, Options{}, s); err != nil {
+		t.Fatalf("MetricLine(): %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{"<title>Heart rate variability</title>", ">ms</text>", `stroke="` + HRVColor + `"`, `<circle `} {
+		if !strings.Contains(got, want) {
+			t.Errorf("MetricLine(): got %s, want it to contain %s", got, want)
+		}
+	}
+	if n := strings.Count(got, "<polyline "); n != 1 {
+		t.Errorf("MetricLine(): got %d lines, want 1 and a dot after the gap", n)
+	}
+}
+
+func TestSleepStages(t *testing.T) {
+	night := func(id, d int, nap bool) whoop.Sleep {
+		start := time.Date(2022, 11, d, 7, 0, 0, 0, time.UTC)
+		s := whoop.Sleep{ID: id, Start: &start, TimezoneOffset: whooptest.Ptr("-08:00"), Nap: nap, ScoreState: whooptest.Ptr("SCORED")}
+		st := &s.Score.StageSummary
+		st.TotalLightSleepTimeMilli = int(4 * time.Hour / time.Millisecond)
+		st.TotalSlowWaveSleepTimeMilli = int(90 * time.Minute / time.Millisecond)
+		st.TotalRemSleepTimeMilli = int(2 * time.Hour / time.Millisecond)
+		st.TotalAwakeTimeMilli = int(30 * time.Minute / time.Millisecond)
+		return s
+	}
+	sleeps := []whoop.Sleep{night(2, 3, false), night(1, 2, false), night(3, 2, true)}
+	unscored := night(4, 4, false)
+	unscored.ScoreState = whooptest.Ptr("PENDING_SCORE")
+	sleeps = append(sleeps, unscored)
+
+	var b strings.Builder
+	if err := SleepStages(&b, Options{}, sleeps); err != nil {
+		t.Fatalf("SleepStages(): %v", err)
+	}
+	got := b.String()
+	if i, j := strings.Index(got, ">Nov 2</text>"), strings.Index(got, ">Nov 3</text>"); i < 0 || j < i {
+		t.Errorf("SleepStages(): got %s, want Nov 2 then Nov 3", got)
+	}
+	for _, want := range []string{">Light</text>", ">SWS</text>", ">REM</text>", ">Awake</text>", "<title>Sleep stages</title>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("SleepStages(): got %s, want it to contain %s", got, want)
+		}
+	}
+	if n := strings.Count(got, `fill="`+REMColor+`"`); n != 3 {
+		t.Errorf("SleepStages(): got %d REM rects, want 2 nights and a legend entry", n)
+	}
+}
+
+func TestStrainRecovery(t *testing.T) {
+	var cycles []whoop.Cycle
+	var recoveries []whoop.Recovery
+	for d := 1; d <= 3; d++ {
+		start := time.Date(2022, 11, d, 7, 0, 0, 0, time.UTC)
+		end := start.Add(24 * time.Hour)
+		c := whoop.Cycle{ID: d, Start: &start, End: &end, ScoreState: whooptest.Ptr("SCORED")}
+		c.Score.Strain = float64(5 * d)
+		cycles = append(cycles, c)
+		r := whoop.Recovery{CycleID: d, ScoreState: whooptest.Ptr("SCORED")}
+		r.Score.RecoveryScore = float64(100 - 30*d)
+		recoveries = append(recoveries, r)
+	}
+
+	var b strings.Builder
+	if err := StrainRecovery(&b, Options{}, cycles, recoveries); err != nil {
+		t.Fatalf("StrainRecovery(): %v", err)
+	}
+	got := b.String()
+	if n := strings.Count(got, "<circle "); n != 2 {
+		t.Errorf("StrainRecovery(): got %d points, want 2", n)
+	}
+	for _, want := range []string{`fill="` + BandColor(analytics.Yellow) + `"`, `fill="` + BandColor(analytics.Red) + `"`, ">100</text>", ">Strain</text>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("StrainRecovery(): got %s, want it to contain %s", got, want)
+		}
+	}
+}
+
+func TestZones(t *testing.T) {
+	var b strings.Builder
+	z := analytics.ZoneDurations{0, 30 * time.Minute, 20 * time.Minute, 10 * time.Minute, 5 * time.Minute, 0}
+	if err := Zones(&b, Options{}, z); err != nil {
+		t.Fatalf("Zones(): %v", err)
+	}
+	got := b.String()
+	if n := strings.Count(got, "<rect "); n != 4 {
+		t.Errorf("Zones(): got %d bars, want 4 zones with time", n)
+	}
+	if !strings.Contains(got, ">Zone 5</text>") || !strings.Contains(got, `fill="`+ZoneColors[1]+`"`) {
+		t.Errorf("Zones(): got %s, want labelled and colored zones", got)
+	}
+}
+
+func TestWeeklyZones(t *testing.T) {
+	weeks := []analytics.ZoneWeek{{Start: day(7)}, {Start: day(14)}}
+	weeks[0].Zones = analytics.ZoneDurations{1: time.Hour, 3: time.Hour}
+	weeks[1].Zones = analytics.ZoneDurations{2: 2 * time.Hour}
+
+	var b strings.Builder
+	if err := WeeklyZones(&b, Options{}, weeks); err != nil {
+		t.Fatalf("WeeklyZones(): %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{">Nov 7</text>", ">Nov 14</text>", ">Zone 0</text>", ">Zone 5</text>"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WeeklyZones(): got %s, want it to contain %s", got, want)
+		}
+	}
+	if n := strings.Count(got, "<rect "); n != 3+6 {
+		t.Errorf("WeeklyZones(): got %d rects, want 3 segments and 6 legend entries", n)
+	}
+}
@@ -2,6 +2,7 @@ package whoop
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -35,6 +36,42 @@ type Cycle struct {
 		AverageHeartRate float64 `json:"average_heart_rate,omitempty"` // The user's average heart rate during the cycle.
 		MaxHeartRate     float64 `json:"max_heart_rate,omitempty"`     // The user's max heart rate during the cycle.
 	} `json:"score,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields returned by the API that are not part of the model, keyed by their dotted path.
+}
+
+// UnmarshalJSON unmarshals data into c, keeping unknown fields in c.Extra.
+func (c *Cycle) UnmarshalJSON(data []byte) error {
+	type cycle Cycle
+	var v cycle
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*c = Cycle(v)
+	c.Extra = extra
+	return nil
+}
+
+// MarshalJSON marshals c, including the unknown fields in c.Extra.
+func (c Cycle) MarshalJSON() ([]byte, error) {
+	type cycle Cycle
+	return marshalWithExtra(cycle(c), c.Extra)
+}
+
+func (c Cycle) unknownFields() []string {
+	return extraKeys("", c.Extra)
+}
+
+// Scored reports whether the cycle has been scored. The cycle in progress
+// is scored too, but its score changes until it ends.
+func (c Cycle) Scored() bool {
+	return c.ScoreState != nil && *c.ScoreState == "SCORED"
+}
+
+// settled reports whether the cycle has ended and has been scored.
+func (c Cycle) settled() bool {
+	return c.End != nil && c.Scored()
 }
 
 // GetOne retrieves a single physiological cycle record for the specified id.
@@ -54,6 +91,26 @@ type CycleListAllResp struct {
 	NextToken *string `json:"next_token"`
 }
 
+func (r CycleListAllResp) unknownFields() []string {
+	var fields []string
+	for i, c := range r.Records {
+		fields = append(fields, extraKeys(fmt.Sprintf("records[%v].", i), c.Extra)...)
+	}
+	return fields
+}
+
+func (CycleListAllResp) page() {}
+
+// settled reports whether every record in the page has settled.
+func (r CycleListAllResp) settled() bool {
+	for _, c := range r.Records {
+		if !c.settled() {
+			return false
+		}
+	}
+	return len(r.Records) > 0
+}
+
 // ListAll lists all physiological cycle records for the authenticated user.
 // Results are paginated and sorted by start time in descending order.
 //
@@ -9,6 +9,30 @@ import (
 	"time"
 )
 
+func TestCycle_Scored(t *testing.T) {
+	scored, pending := "SCORED", "PENDING_SCORE"
+	end := time.Date(2022, 11, 27, 8, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		cycle   Cycle
+		scored  bool
+		settled bool
+	}{
+		{Cycle{ScoreState: &scored, End: &end}, true, true},
+		{Cycle{ScoreState: &scored}, true, false},
+		{Cycle{ScoreState: &pending, End: &end}, false, false},
+		{Cycle{}, false, false},
+	}
+
+	for _, test := range testCases {
+		if got := test.cycle.Scored(); got != test.scored {
+			t.Errorf("Cycle.Scored(): got %v, want %v", got, test.scored)
+		}
+		if got := test.cycle.settled(); got != test.settled {
+			t.Errorf("Cycle.settled(): got %v, want %v", got, test.settled)
+		}
+	}
+}
+
 func TestCycleService_ListAll(t *testing.T) {
 	client, mux, _, teardown := setup()
 	defer teardown()
@@ -0,0 +1,261 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+const (
+	appleHealthSource     = "WHOOP"
+	appleHealthDateLayout = "2006-01-02 15:04:05 -0700"
+)
+
+// HealthKitActivityTypes maps WHOOP sport IDs to HealthKit workout activity
+// types. Sports without an equivalent are exported as HKWorkoutActivityTypeOther.
+var HealthKitActivityTypes = map[int]string{
+	0:   "HKWorkoutActivityTypeRunning",
+	1:   "HKWorkoutActivityTypeCycling",
+	16:  "HKWorkoutActivityTypeBaseball",
+	17:  "HKWorkoutActivityTypeBasketball",
+	18:  "HKWorkoutActivityTypeRowing",
+	19:  "HKWorkoutActivityTypeFencing",
+	20:  "HKWorkoutActivityTypeHockey",
+	21:  "HKWorkoutActivityTypeAmericanFootball",
+	22:  "HKWorkoutActivityTypeGolf",
+	24:  "HKWorkoutActivityTypeHockey",
+	25:  "HKWorkoutActivityTypeLacrosse",
+	27:  "HKWorkoutActivityTypeRugby",
+	28:  "HKWorkoutActivityTypeSailing",
+	29:  "HKWorkoutActivityTypeDownhillSkiing",
+	30:  "HKWorkoutActivityTypeSoccer",
+	31:  "HKWorkoutActivityTypeSoftball",
+	32:  "HKWorkoutActivityTypeSquash",
+	33:  "HKWorkoutActivityTypeSwimming",
+	34:  "HKWorkoutActivityTypeTennis",
+	35:  "HKWorkoutActivityTypeTrackAndField",
+	36:  "HKWorkoutActivityTypeVolleyball",
+	37:  "HKWorkoutActivityTypeWaterPolo",
+	38:  "HKWorkoutActivityTypeWrestling",
+	39:  "HKWorkoutActivityTypeBoxing",
+	42:  "HKWorkoutActivityTypeSocialDance",
+	43:  "HKWorkoutActivityTypePilates",
+	44:  "HKWorkoutActivityTypeYoga",
+	45:  "HKWorkoutActivityTypeTraditionalStrengthTraining",
+	47:  "HKWorkoutActivityTypeCrossCountrySkiing",
+	48:  "HKWorkoutActivityTypeFunctionalStrengthTraining",
+	49:  "HKWorkoutActivityTypeMixedCardio",
+	51:  "HKWorkoutActivityTypeGymnastics",
+	52:  "HKWorkoutActivityTypeHiking",
+	53:  "HKWorkoutActivityTypeEquestrianSports",
+	55:  "HKWorkoutActivityTypePaddleSports",
+	56:  "HKWorkoutActivityTypeMartialArts",
+	57:  "HKWorkoutActivityTypeCycling",
+	59:  "HKWorkoutActivityTypeTraditionalStrengthTraining",
+	60:  "HKWorkoutActivityTypeClimbing",
+	61:  "HKWorkoutActivityTypePaddleSports",
+	62:  "HKWorkoutActivityTypeSwimBikeRun",
+	63:  "HKWorkoutActivityTypeWalking",
+	64:  "HKWorkoutActivityTypeSurfingSports",
+	65:  "HKWorkoutActivityTypeElliptical",
+	66:  "HKWorkoutActivityTypeStairClimbing",
+	70:  "HKWorkoutActivityTypeMindAndBody",
+	83:  "HKWorkoutActivityTypeClimbing",
+	84:  "HKWorkoutActivityTypeJumpRope",
+	85:  "HKWorkoutActivityTypeAustralianFootball",
+	86:  "HKWorkoutActivityTypeSkatingSports",
+	91:  "HKWorkoutActivityTypeSnowboarding",
+	96:  "HKWorkoutActivityTypeHighIntensityIntervalTraining",
+	97:  "HKWorkoutActivityTypeCycling",
+	98:  "HKWorkoutActivityTypeMartialArts",
+	100: "HKWorkoutActivityTypeCricket",
+	101: "HKWorkoutActivityTypePickleball",
+	102: "HKWorkoutActivityTypeSkatingSports",
+	103: "HKWorkoutActivityTypeBoxing",
+	105: "HKWorkoutActivityTypeWheelchairRunPace",
+	107: "HKWorkoutActivityTypeBarre",
+	126: "HKWorkoutActivityTypeCycling",
+	127: "HKWorkoutActivityTypeKickboxing",
+	128: "HKWorkoutActivityTypeFlexibility",
+}
+
+// Sleep analysis categories written for each sleep stage.
+const (
+	sleepInBed = "HKCategoryValueSleepAnalysisInBed"
+	sleepAwake = "HKCategoryValueSleepAnalysisAwake"
+	sleepCore  = "HKCategoryValueSleepAnalysisAsleepCore"
+	sleepDeep  = "HKCategoryValueSleepAnalysisAsleepDeep"
+	sleepREM   = "HKCategoryValueSleepAnalysisAsleepREM"
+)
+
+type healthData struct {
+	XMLName    xml.Name        `xml:"HealthData"`
+	Locale     string          `xml:"locale,attr"`
+	ExportDate valueAttr       `xml:"ExportDate"`
+	Records    []healthRecord  `xml:"Record"`
+	Workouts   []healthWorkout `xml:"Workout"`
+}
+
+type valueAttr struct {
+	Value string `xml:"value,attr"`
+}
+
+type healthRecord struct {
+	Type         string `xml:"type,attr"`
+	SourceName   string `xml:"sourceName,attr"`
+	Unit         string `xml:"unit,attr,omitempty"`
+	CreationDate string `xml:"creationDate,attr,omitempty"`
+	StartDate    string `xml:"startDate,attr"`
+	EndDate      string `xml:"endDate,attr"`
+	Value        string `xml:"value,attr"`
+}
+
+type healthWorkout struct {
+	ActivityType          string `xml:"workoutActivityType,attr"`
+	Duration              string `xml:"duration,attr"`
+	DurationUnit          string `xml:"durationUnit,attr"`
+	TotalDistance         string `xml:"totalDistance,attr,omitempty"`
+	TotalDistanceUnit     string `xml:"totalDistanceUnit,attr,omitempty"`
+	TotalEnergyBurned     string `xml:"totalEnergyBurned,attr,omitempty"`
+	TotalEnergyBurnedUnit string `xml:"totalEnergyBurnedUnit,attr,omitempty"`
+	SourceName            string `xml:"sourceName,attr"`
+	CreationDate          string `xml:"creationDate,attr,omitempty"`
+	StartDate             string `xml:"startDate,attr"`
+	EndDate               string `xml:"endDate,attr"`
+}
+
+// WriteAppleHealth writes data as an Apple Health export.xml document.
+//
+// Recoveries are written as resting heart rate, heart rate variability
+// and oxygen saturation records. Note that WHOOP measures heart rate
+// variability as RMSSD, which is written as
+// HKQuantityTypeIdentifierHeartRateVariabilitySDNN as HealthKit has no
+// RMSSD type. Sleeps are written as an in bed sleep analysis record followed
+// by one record per stage; since the API only reports the total time of
+// each stage, stage records are laid out back to back from the start of the
+// sleep. Workouts are written with their sport mapped to a HealthKit
+// activity type using HealthKitActivityTypes.
+func WriteAppleHealth(w io.Writer, data HealthData) error {
+	doc := healthData{
+		Locale:     "en_US",
+		ExportDate: valueAttr{data.exportDate().Format(appleHealthDateLayout)},
+	}
+	for i := range data.Recoveries {
+		doc.Records = append(doc.Records, appleRecoveryRecords(&data.Recoveries[i])...)
+	}
+	for i := range data.Sleeps {
+		doc.Records = append(doc.Records, appleSleepRecords(&data.Sleeps[i])...)
+	}
+	for i := range data.Workouts {
+		if wo, ok := appleWorkout(&data.Workouts[i]); ok {
+			doc.Workouts = append(doc.Workouts, wo)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", " ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func appleDate(t time.Time, zone *string) string {
+	return t.In(whoop.ParseOffset(zone)).Format(appleHealthDateLayout)
+}
+
+func appleRecoveryRecords(r *whoop.Recovery) []healthRecord {
+	if !r.Scored() || r.CreatedAt == nil {
+		return nil
+	}
+	date := appleDate(*r.CreatedAt, nil)
+	record := func(typ, unit string, value float64) healthRecord {
+		return healthRecord{Type: typ, SourceName: appleHealthSource, Unit: unit, CreationDate: date, StartDate: date, EndDate: date, Value: formatFloat(value)}
+	}
+	records := []healthRecord{
+		record("HKQuantityTypeIdentifierRestingHeartRate", "count/min", r.Score.RestingHeartRate),
+		record("HKQuantityTypeIdentifierHeartRateVariabilitySDNN", "ms", r.Score.HrvRmssdMilli),
+	}
+	if r.Score.Spo2Percentage != 0 {
+		records = append(records, record("HKQuantityTypeIdentifierOxygenSaturation", "%", r.Score.Spo2Percentage/100))
+	}
+	return records
+}
+
+func appleSleepRecords(s *whoop.Sleep) []healthRecord {
+	if !s.Scored() || s.Start == nil || s.End == nil {
+		return nil
+	}
+	const sleepType = "HKCategoryTypeIdentifierSleepAnalysis"
+	created := ""
+	if s.CreatedAt != nil {
+		created = appleDate(*s.CreatedAt, s.TimezoneOffset)
+	}
+	records := []healthRecord{{
+		Type: sleepType, SourceName: appleHealthSource, CreationDate: created,
+		StartDate: appleDate(*s.Start, s.TimezoneOffset), EndDate: appleDate(*s.End, s.TimezoneOffset), Value: sleepInBed,
+	}}
+	st := s.Score.StageSummary
+	t := *s.Start
+	for _, stage := range []struct {
+		value string
+		milli int
+	}{
+		{sleepAwake, st.TotalAwakeTimeMilli},
+		{sleepCore, st.TotalLightSleepTimeMilli},
+		{sleepDeep, st.TotalSlowWaveSleepTimeMilli},
+		{sleepREM, st.TotalRemSleepTimeMilli},
+	} {
+		if stage.milli <= 0 {
+			continue
+		}
+		end := t.Add(time.Duration(stage.milli) * time.Millisecond)
+		records = append(records, healthRecord{
+			Type: sleepType, SourceName: appleHealthSource, CreationDate: created,
+			StartDate: appleDate(t, s.TimezoneOffset), EndDate: appleDate(end, s.TimezoneOffset), Value: stage.value,
+		})
+		t = end
+	}
+	if s.Score.RespiratoryRate != 0 {
+		records = append(records, healthRecord{
+			Type: "HKQuantityTypeIdentifierRespiratoryRate", SourceName: appleHealthSource, Unit: "count/min", CreationDate: created,
+			StartDate: appleDate(*s.Start, s.TimezoneOffset), EndDate: appleDate(*s.End, s.TimezoneOffset), Value: formatFloat(s.Score.RespiratoryRate),
+		})
+	}
+	return records
+}
+
+func appleWorkout(w *whoop.Workout) (healthWorkout, bool) {
+	if !w.Scored() || w.Start == nil || w.End == nil {
+		return healthWorkout{}, false
+	}
+	activity, ok := HealthKitActivityTypes[w.SportID]
+	if !ok {
+		activity = "HKWorkoutActivityTypeOther"
+	}
+	wo := healthWorkout{
+		ActivityType:          activity,
+		Duration:              formatFloat(w.End.Sub(*w.Start).Minutes()),
+		DurationUnit:          "min",
+		TotalEnergyBurned:     formatFloat(w.Score.Kilojoule / kilojoulesPerKilocalorie),
+		TotalEnergyBurnedUnit: "kcal",
+		SourceName:            appleHealthSource,
+		StartDate:             appleDate(*w.Start, w.TimezoneOffset),
+		EndDate:               appleDate(*w.End, w.TimezoneOffset),
+	}
+	if w.CreatedAt != nil {
+		wo.CreationDate = appleDate(*w.CreatedAt, w.TimezoneOffset)
+	}
+	if w.Score.DistanceMeter > 0 {
+		wo.TotalDistance = fmt.Sprintf("%.3f", w.Score.DistanceMeter/1000)
+		wo.TotalDistanceUnit = "km"
+	}
+	return wo, true
+}
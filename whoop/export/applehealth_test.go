@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func testWorkout() whoop.Workout {
+	start := time.Date(2022, 11, 27, 16, 0, 0, 0, time.UTC)
+	end := start.Add(45 * time.Minute)
+	wo := whoop.Workout{ID: 7, UserID: 10, Start: &start, End: &end, TimezoneOffset: whooptest.Ptr("-08:00"), SportID: 0, SportName: whooptest.Ptr("Running"), ScoreState: whooptest.Ptr("SCORED")}
+	wo.Score.Strain = 12.5
+	wo.Score.Kilojoule = 2092
+	wo.Score.DistanceMeter = 8000
+	return wo
+}
+
+func TestWriteAppleHealth(t *testing.T) {
+	created := time.Date(2022, 11, 27, 15, 0, 0, 0, time.UTC)
+	r := whoop.Recovery{CycleID: 1, CreatedAt: &created, ScoreState: whooptest.Ptr("SCORED")}
+	r.Score.RestingHeartRate = 52
+	r.Score.HrvRmssdMilli = 61.5
+	s := testSleep()
+	end := s.Start.Add(30 * time.Minute)
+	s.End = &end
+	s.Score.StageSummary.TotalLightSleepTimeMilli = 600000
+	pending := testWorkout()
+	pending.ScoreState = whooptest.Ptr("PENDING_SCORE")
+
+	var buf bytes.Buffer
+	err := WriteAppleHealth(&buf, HealthData{
+		ExportDate: time.Date(2022, 11, 28, 0, 0, 0, 0, time.UTC),
+		Recoveries: []whoop.Recovery{r},
+		Sleeps:     []whoop.Sleep{s},
+		Workouts:   []whoop.Workout{testWorkout(), pending},
+	})
+	if err != nil {
+		t.Fatalf("WriteAppleHealth(): expected nil error, got %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		`<ExportDate value="2022-11-28 00:00:00 +0000"></ExportDate>`,
+		`<Record type="HKQuantityTypeIdentifierRestingHeartRate" sourceName="WHOOP" unit="count/min" creationDate="2022-11-27 15:00:00 +0000" startDate="2022-11-27 15:00:00 +0000" endDate="2022-11-27 15:00:00 +0000" value="52"></Record>`,
+		`<Record type="HKQuantityTypeIdentifierHeartRateVariabilitySDNN" sourceName="WHOOP" unit="ms" creationDate="2022-11-27 15:00:00 +0000" startDate="2022-11-27 15:00:00 +0000" endDate="2022-11-27 15:00:00 +0000" value="61.5"></Record>`,
+		`startDate="2022-11-26 22:30:00 -0800" endDate="2022-11-26 23:00:00 -0800" value="HKCategoryValueSleepAnalysisInBed"`,
+		`startDate="2022-11-26 22:30:00 -0800" endDate="2022-11-26 22:40:00 -0800" value="HKCategoryValueSleepAnalysisAsleepCore"`,
+		`startDate="2022-11-26 22:40:00 -0800" endDate="2022-11-26 22:41:30 -0800" value="HKCategoryValueSleepAnalysisAsleepREM"`,
+		`<Workout workoutActivityType="HKWorkoutActivityTypeRunning" duration="45" durationUnit="min" totalDistance="8.000" totalDistanceUnit="km" totalEnergyBurned="500" totalEnergyBurnedUnit="kcal" sourceName="WHOOP" startDate="2022-11-27 08:00:00 -0800" endDate="2022-11-27 08:45:00 -0800"></Workout>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteAppleHealth(): expected output to contain\n%v\ngot\n%v", want, got)
+		}
+	}
+	if n := strings.Count(got, "<Workout "); n != 1 {
+		t.Errorf("WriteAppleHealth(): expected 1 workout, got %v", n)
+	}
+	if strings.Contains(got, "OxygenSaturation") || strings.Contains(got, "SleepAnalysisAwake") {
+		t.Errorf("WriteAppleHealth(): expected empty metrics to be skipped, got\n%v", got)
+	}
+}
+
+func TestWriteAppleHealth_otherSport(t *testing.T) {
+	wo := testWorkout()
+	wo.SportID = -1
+	var buf bytes.Buffer
+	WriteAppleHealth(&buf, HealthData{Workouts: []whoop.Workout{wo}})
+	if !strings.Contains(buf.String(), `workoutActivityType="HKWorkoutActivityTypeOther"`) {
+		t.Errorf("WriteAppleHealth(): expected HKWorkoutActivityTypeOther, got\n%v", buf.String())
+	}
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func textCol[T any](name string, value func(r *T) any) column[T] {
+	return column[T]{name: name, kind: kindText, value: value}
+}
+
+func timeCol[T any](name string, zone func(r *T) *string, value func(r *T) *time.Time) column[T] {
+	return column[T]{name: name, kind: kindTime, zone: zone, value: func(r *T) any { return value(r) }}
+}
+
+func scoreCol[T any](name string, k kind, scored func(r *T) bool, value func(r *T) any) column[T] {
+	return column[T]{name: name, kind: k, score: true, scored: scored, value: value}
+}
+
+var (
+	cycleZone  = func(c *whoop.Cycle) *string { return c.TimezoneOffset }
+	cycleScore = func(c *whoop.Cycle) bool { return c.Scored() }
+)
+
+var cycleColumns = []column[whoop.Cycle]{
+	textCol("id", func(c *whoop.Cycle) any { return c.ID }),
+	textCol("user_id", func(c *whoop.Cycle) any { return c.UserID }),
+	timeCol("created_at", cycleZone, func(c *whoop.Cycle) *time.Time { return c.CreatedAt }),
+	timeCol("updated_at", cycleZone, func(c *whoop.Cycle) *time.Time { return c.UpdatedAt }),
+	timeCol("start", cycleZone, func(c *whoop.Cycle) *time.Time { return c.Start }),
+	timeCol("end", cycleZone, func(c *whoop.Cycle) *time.Time { return c.End }),
+	textCol("timezone_offset", func(c *whoop.Cycle) any { return c.TimezoneOffset }),
+	textCol("score_state", func(c *whoop.Cycle) any { return c.ScoreState }),
+	scoreCol("score_strain", kindText, cycleScore, func(c *whoop.Cycle) any { return c.Score.Strain }),
+	scoreCol("score_kilojoule", kindEnergy, cycleScore, func(c *whoop.Cycle) any { return c.Score.Kilojoule }),
+	scoreCol("score_average_heart_rate", kindText, cycleScore, func(c *whoop.Cycle) any { return c.Score.AverageHeartRate }),
+	scoreCol("score_max_heart_rate", kindText, cycleScore, func(c *whoop.Cycle) any { return c.Score.MaxHeartRate }),
+}
+
+var recoveryScore = func(r *whoop.Recovery) bool { return r.Scored() }
+
+var recoveryColumns = []column[whoop.Recovery]{
+	textCol("cycle_id", func(r *whoop.Recovery) any { return r.CycleID }),
+	textCol("sleep_id", func(r *whoop.Recovery) any { return r.SleepID }),
+	textCol("user_id", func(r *whoop.Recovery) any { return r.UserID }),
+	timeCol("created_at", nil, func(r *whoop.Recovery) *time.Time { return r.CreatedAt }),
+	timeCol("updated_at", nil, func(r *whoop.Recovery) *time.Time { return r.UpdatedAt }),
+	textCol("score_state", func(r *whoop.Recovery) any { return r.ScoreState }),
+	scoreCol("score_user_calibrating", kindText, recoveryScore, func(r *whoop.Recovery) any { return r.Score.UserCalibrating }),
+	scoreCol("score_recovery_score", kindText, recoveryScore, func(r *whoop.Recovery) any { return r.Score.RecoveryScore }),
+	scoreCol("score_resting_heart_rate", kindText, recoveryScore, func(r *whoop.Recovery) any { return r.Score.RestingHeartRate }),
+	scoreCol("score_hrv_rmssd_milli", kindText, recoveryScore, func(r *whoop.Recovery) any { return r.Score.HrvRmssdMilli }),
+	scoreCol("score_spo2_percentage", kindText, recoveryScore, func(r *whoop.Recovery) any { return r.Score.Spo2Percentage }),
+	scoreCol("score_skin_temp_celsius", kindText, recoveryScore, func(r *whoop.Recovery) any { return r.Score.SkinTempCelsius }),
+}
+
+var (
+	sleepZone  = func(s *whoop.Sleep) *string { return s.TimezoneOffset }
+	sleepScore = func(s *whoop.Sleep) bool { return s.Scored() }
+)
+
+var sleepColumns = []column[whoop.Sleep]{
+	textCol("id", func(s *whoop.Sleep) any { return s.ID }),
+	textCol("user_id", func(s *whoop.Sleep) any { return s.UserID }),
+	timeCol("created_at", sleepZone, func(s *whoop.Sleep) *time.Time { return s.CreatedAt }),
+	timeCol("updated_at", sleepZone, func(s *whoop.Sleep) *time.Time { return s.UpdatedAt }),
+	timeCol("start", sleepZone, func(s *whoop.Sleep) *time.Time { return s.Start }),
+	timeCol("end", sleepZone, func(s *whoop.Sleep) *time.Time { return s.End }),
+	textCol("timezone_offset", func(s *whoop.Sleep) any { return s.TimezoneOffset }),
+	textCol("nap", func(s *whoop.Sleep) any { return s.Nap }),
+	textCol("score_state", func(s *whoop.Sleep) any { return s.ScoreState }),
+	scoreCol("score_stage_summary_total_in_bed_time_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.StageSummary.TotalInBedTimeMilli }),
+	scoreCol("score_stage_summary_total_awake_time_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.StageSummary.TotalAwakeTimeMilli }),
+	scoreCol("score_stage_summary_total_no_data_time_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.StageSummary.TotalNoDataTimeMilli }),
+	scoreCol("score_stage_summary_total_light_sleep_time_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.StageSummary.TotalLightSleepTimeMilli }),
+	scoreCol("score_stage_summary_total_slow_wave_sleep_time_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.StageSummary.TotalSlowWaveSleepTimeMilli }),
+	scoreCol("score_stage_summary_total_rem_sleep_time_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.StageSummary.TotalRemSleepTimeMilli }),
+	scoreCol("score_stage_summary_sleep_cycle_count", kindText, sleepScore, func(s *whoop.Sleep) any { return s.Score.StageSummary.SleepCycleCount }),
+	scoreCol("score_stage_summary_disturbance_count", kindText, sleepScore, func(s *whoop.Sleep) any { return s.Score.StageSummary.DisturbanceCount }),
+	scoreCol("score_sleep_needed_baseline_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.SleepNeeded.BaselineMilli }),
+	scoreCol("score_sleep_needed_need_from_sleep_debt_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.SleepNeeded.NeedFromSleepDebtMilli }),
+	scoreCol("score_sleep_needed_need_from_recent_strain_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.SleepNeeded.NeedFromRecentStrainMilli }),
+	scoreCol("score_sleep_needed_need_from_recent_nap_milli", kindDuration, sleepScore, func(s *whoop.Sleep) any { return s.Score.SleepNeeded.NeedFromRecentNapMilli }),
+	scoreCol("score_respiratory_rate", kindText, sleepScore, func(s *whoop.Sleep) any { return s.Score.RespiratoryRate }),
+	scoreCol("score_sleep_performance_percentage", kindText, sleepScore, func(s *whoop.Sleep) any { return s.Score.SleepPerformancePercentage }),
+	scoreCol("score_sleep_consistency_percentage", kindText, sleepScore, func(s *whoop.Sleep) any { return s.Score.SleepConsistencyPercentage }),
+	scoreCol("score_sleep_efficiency_percentage", kindText, sleepScore, func(s *whoop.Sleep) any { return s.Score.SleepEfficiencyPercentage }),
+}
+
+var (
+	workoutZone  = func(w *whoop.Workout) *string { return w.TimezoneOffset }
+	workoutScore = func(w *whoop.Workout) bool { return w.Scored() }
+)
+
+var workoutColumns = []column[whoop.Workout]{
+	textCol("id", func(w *whoop.Workout) any { return w.ID }),
+	textCol("user_id", func(w *whoop.Workout) any { return w.UserID }),
+	timeCol("created_at", workoutZone, func(w *whoop.Workout) *time.Time { return w.CreatedAt }),
+	timeCol("updated_at", workoutZone, func(w *whoop.Workout) *time.Time { return w.UpdatedAt }),
+	timeCol("start", workoutZone, func(w *whoop.Workout) *time.Time { return w.Start }),
+	timeCol("end", workoutZone, func(w *whoop.Workout) *time.Time { return w.End }),
+	textCol("timezone_offset", func(w *whoop.Workout) any { return w.TimezoneOffset }),
+	textCol("sport_id", func(w *whoop.Workout) any { return w.SportID }),
+	textCol("sport_name", func(w *whoop.Workout) any { return w.SportName }),
+	textCol("score_state", func(w *whoop.Workout) any { return w.ScoreState }),
+	scoreCol("score_strain", kindText, workoutScore, func(w *whoop.Workout) any { return w.Score.Strain }),
+	scoreCol("score_average_heart_rate", kindText, workoutScore, func(w *whoop.Workout) any { return w.Score.AverageHeartRate }),
+	scoreCol("score_max_heart_rate", kindText, workoutScore, func(w *whoop.Workout) any { return w.Score.MaxHeartRate }),
+	scoreCol("score_kilojoule", kindEnergy, workoutScore, func(w *whoop.Workout) any { return w.Score.Kilojoule }),
+	scoreCol("score_percent_recorded", kindText, workoutScore, func(w *whoop.Workout) any { return w.Score.PercentRecorded }),
+	scoreCol("score_distance_meter", kindText, workoutScore, func(w *whoop.Workout) any { return w.Score.DistanceMeter }),
+	scoreCol("score_altitude_gain_meter", kindText, workoutScore, func(w *whoop.Workout) any { return w.Score.AltitudeGainMeter }),
+	scoreCol("score_altitude_change_meter", kindText, workoutScore, func(w *whoop.Workout) any { return w.Score.AltitudeChangeMeter }),
+	scoreCol("score_zone_duration_zone_zero_milli", kindDuration, workoutScore, func(w *whoop.Workout) any { return w.Score.ZoneDuration.ZoneZeroMilli }),
+	scoreCol("score_zone_duration_zone_one_milli", kindDuration, workoutScore, func(w *whoop.Workout) any { return w.Score.ZoneDuration.ZoneOneMilli }),
+	scoreCol("score_zone_duration_zone_two_milli", kindDuration, workoutScore, func(w *whoop.Workout) any { return w.Score.ZoneDuration.ZoneTwoMilli }),
+	scoreCol("score_zone_duration_zone_three_milli", kindDuration, workoutScore, func(w *whoop.Workout) any { return w.Score.ZoneDuration.ZoneThreeMilli }),
+	scoreCol("score_zone_duration_zone_four_milli", kindDuration, workoutScore, func(w *whoop.Workout) any { return w.Score.ZoneDuration.ZoneFourMilli }),
+	scoreCol("score_zone_duration_zone_five_milli", kindDuration, workoutScore, func(w *whoop.Workout) any { return w.Score.ZoneDuration.ZoneFiveMilli }),
+}
+
+var (
+	dailyZone  = func(d *DailySummary) *string { return d.Cycle.TimezoneOffset }
+	dailyCycle = func(d *DailySummary) bool { return d.Cycle.Scored() }
+	dailyRecov = func(d *DailySummary) bool { return d.Recovery != nil && d.Recovery.Scored() }
+	dailySleep = func(d *DailySummary) bool { return d.Sleep != nil && d.Sleep.Scored() }
+)
+
+var dailySummaryColumns = []column[DailySummary]{
+	textCol("cycle_id", func(d *DailySummary) any { return d.Cycle.ID }),
+	textCol("user_id", func(d *DailySummary) any { return d.Cycle.UserID }),
+	timeCol("cycle_start", dailyZone, func(d *DailySummary) *time.Time { return d.Cycle.Start }),
+	timeCol("cycle_end", dailyZone, func(d *DailySummary) *time.Time { return d.Cycle.End }),
+	textCol("timezone_offset", func(d *DailySummary) any { return d.Cycle.TimezoneOffset }),
+	scoreCol("cycle_strain", kindText, dailyCycle, func(d *DailySummary) any { return d.Cycle.Score.Strain }),
+	scoreCol("cycle_kilojoule", kindEnergy, dailyCycle, func(d *DailySummary) any { return d.Cycle.Score.Kilojoule }),
+	scoreCol("cycle_average_heart_rate", kindText, dailyCycle, func(d *DailySummary) any { return d.Cycle.Score.AverageHeartRate }),
+	scoreCol("recovery_score", kindText, dailyRecov, func(d *DailySummary) any { return d.Recovery.Score.RecoveryScore }),
+	scoreCol("recovery_resting_heart_rate", kindText, dailyRecov, func(d *DailySummary) any { return d.Recovery.Score.RestingHeartRate }),
+	scoreCol("recovery_hrv_rmssd_milli", kindText, dailyRecov, func(d *DailySummary) any { return d.Recovery.Score.HrvRmssdMilli }),
+	scoreCol("recovery_spo2_percentage", kindText, dailyRecov, func(d *DailySummary) any { return d.Recovery.Score.Spo2Percentage }),
+	scoreCol("recovery_skin_temp_celsius", kindText, dailyRecov, func(d *DailySummary) any { return d.Recovery.Score.SkinTempCelsius }),
+	textCol("sleep_id", func(d *DailySummary) any {
+		if d.Sleep == nil {
+			return nil
+		}
+		return d.Sleep.ID
+	}),
+	scoreCol("sleep_asleep_milli", kindDuration, dailySleep, func(d *DailySummary) any { return int(d.Sleep.TimeAsleep().Milliseconds()) }),
+	scoreCol("sleep_in_bed_milli", kindDuration, dailySleep, func(d *DailySummary) any { return d.Sleep.Score.StageSummary.TotalInBedTimeMilli }),
+	scoreCol("sleep_performance_percentage", kindText, dailySleep, func(d *DailySummary) any { return d.Sleep.Score.SleepPerformancePercentage }),
+	scoreCol("sleep_efficiency_percentage", kindText, dailySleep, func(d *DailySummary) any { return d.Sleep.Score.SleepEfficiencyPercentage }),
+	scoreCol("sleep_respiratory_rate", kindText, dailySleep, func(d *DailySummary) any { return d.Sleep.Score.RespiratoryRate }),
+	textCol("workout_count", func(d *DailySummary) any { return len(d.Workouts) }),
+	column[DailySummary]{name: "workout_kilojoule", kind: kindEnergy, value: func(d *DailySummary) any {
+		var kj float64
+		for _, w := range d.Workouts {
+			kj += w.Score.Kilojoule
+		}
+		return kj
+	}},
+}
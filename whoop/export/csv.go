@@ -0,0 +1,252 @@
+// Package export writes WHOOP records to file formats used by
+// spreadsheets and analytics tools.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// DurationUnit is the unit used to write durations.
+type DurationUnit int
+
+// Duration units.
+const (
+	Milliseconds DurationUnit = iota // Durations as integer milliseconds, as returned by the API.
+	Minutes                          // Durations as decimal minutes.
+)
+
+// EnergyUnit is the unit used to write energy expenditure.
+type EnergyUnit int
+
+// Energy units.
+const (
+	Kilojoules   EnergyUnit = iota // Energy in kilojoules, as returned by the API.
+	Kilocalories                   // Energy in kilocalories.
+)
+
+const kilojoulesPerKilocalorie = 4.184
+
+// CSVOptions configures a CSVWriter.
+type CSVOptions struct {
+	// Columns lists the columns to write, in order, by their stable name
+	// as returned by the Columns method of the writer. If empty, all
+	// columns are written.
+	Columns []string
+
+	Duration DurationUnit // Unit of duration columns. Defaults to Milliseconds.
+	Energy   EnergyUnit   // Unit of energy columns. Defaults to Kilojoules.
+
+	// UTC formats times in UTC instead of the record's TimezoneOffset.
+	// Records without a TimezoneOffset are always formatted in UTC.
+	UTC bool
+}
+
+// CSVWriter writes records of type T as CSV rows, one record per row.
+// Nested score fields are flattened into columns, and score columns are
+// left empty for records which are not scored. The header is written
+// before the first row.
+type CSVWriter[T any] struct {
+	w           *csv.Writer
+	cols        []column[T]
+	opts        CSVOptions
+	wroteHeader bool
+}
+
+// NewCycleWriter returns a CSVWriter of cycles writing to w.
+func NewCycleWriter(w io.Writer, opts CSVOptions) (*CSVWriter[whoop.Cycle], error) {
+	return newCSVWriter(w, cycleColumns, opts)
+}
+
+// NewRecoveryWriter returns a CSVWriter of recoveries writing to w.
+func NewRecoveryWriter(w io.Writer, opts CSVOptions) (*CSVWriter[whoop.Recovery], error) {
+	return newCSVWriter(w, recoveryColumns, opts)
+}
+
+// NewSleepWriter returns a CSVWriter of sleeps writing to w.
+func NewSleepWriter(w io.Writer, opts CSVOptions) (*CSVWriter[whoop.Sleep], error) {
+	return newCSVWriter(w, sleepColumns, opts)
+}
+
+// NewWorkoutWriter returns a CSVWriter of workouts writing to w.
+func NewWorkoutWriter(w io.Writer, opts CSVOptions) (*CSVWriter[whoop.Workout], error) {
+	return newCSVWriter(w, workoutColumns, opts)
+}
+
+// NewDailySummaryWriter returns a CSVWriter of daily summaries writing to w.
+func NewDailySummaryWriter(w io.Writer, opts CSVOptions) (*CSVWriter[DailySummary], error) {
+	return newCSVWriter(w, dailySummaryColumns, opts)
+}
+
+func newCSVWriter[T any](w io.Writer, all []column[T], opts CSVOptions) (*CSVWriter[T], error) {
+	cols := all
+	if len(opts.Columns) > 0 {
+		byName := make(map[string]column[T], len(all))
+		for _, c := range all {
+			byName[c.name] = c
+		}
+		cols = make([]column[T], len(opts.Columns))
+		for i, name := range opts.Columns {
+			c, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("export: unknown column %q", name)
+			}
+			cols[i] = c
+		}
+	}
+	return &CSVWriter[T]{w: csv.NewWriter(w), cols: cols, opts: opts}, nil
+}
+
+// Columns returns the stable names of the columns written, in order.
+func (w *CSVWriter[T]) Columns() []string {
+	names := make([]string, len(w.cols))
+	for i, c := range w.cols {
+		names[i] = c.name
+	}
+	return names
+}
+
+// Header returns the header row. It matches Columns, except that the
+// names of duration and energy columns reflect the selected units.
+func (w *CSVWriter[T]) Header() []string {
+	header := make([]string, len(w.cols))
+	for i, c := range w.cols {
+		header[i] = c.header(w.opts)
+	}
+	return header
+}
+
+// Write writes records as CSV rows, writing the header first if needed.
+func (w *CSVWriter[T]) Write(records ...T) error {
+	if !w.wroteHeader {
+		if err := w.w.Write(w.Header()); err != nil {
+			return err
+		}
+		w.wroteHeader = true
+	}
+	row := make([]string, len(w.cols))
+	for i := range records {
+		for j, c := range w.cols {
+			row[j] = c.format(&records[i], w.opts)
+		}
+		if err := w.w.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying io.Writer, writing
+// the header if no records have been written.
+func (w *CSVWriter[T]) Flush() error {
+	if !w.wroteHeader {
+		if err := w.Write(); err != nil {
+			return err
+		}
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// kind is the kind of value held by a column, which determines its format.
+type kind int
+
+const (
+	kindText kind = iota
+	kindTime
+	kindDuration
+	kindEnergy
+)
+
+// column is a CSV column of records of type T.
+type column[T any] struct {
+	name  string
+	kind  kind
+	score bool // Left empty for records which are not scored.
+
+	// value returns the value of the column. Times are *time.Time,
+	// durations are milliseconds and energies are kilojoules.
+	value func(r *T) any
+	// zone returns the timezone offset of the record, for time columns.
+	zone func(r *T) *string
+	// scored reports whether the record is scored, for score columns.
+	scored func(r *T) bool
+}
+
+func (c column[T]) header(opts CSVOptions) string {
+	switch {
+	case c.kind == kindDuration && opts.Duration == Minutes:
+		return strings.TrimSuffix(c.name, "_milli") + "_minutes"
+	case c.kind == kindEnergy && opts.Energy == Kilocalories:
+		return strings.TrimSuffix(c.name, "kilojoule") + "kilocalorie"
+	}
+	return c.name
+}
+
+func (c column[T]) format(r *T, opts CSVOptions) string {
+	if c.score && c.scored != nil && !c.scored(r) {
+		return ""
+	}
+	v := c.value(r)
+	switch c.kind {
+	case kindTime:
+		t, _ := v.(*time.Time)
+		var zone *string
+		if c.zone != nil {
+			zone = c.zone(r)
+		}
+		return formatTime(t, zone, opts.UTC)
+	case kindDuration:
+		ms := v.(int)
+		if opts.Duration == Minutes {
+			return formatFloat(float64(ms) / float64(time.Minute/time.Millisecond))
+		}
+		return strconv.Itoa(ms)
+	case kindEnergy:
+		kj := v.(float64)
+		if opts.Energy == Kilocalories {
+			return formatFloat(kj / kilojoulesPerKilocalorie)
+		}
+		return formatFloat(kj)
+	}
+	switch v := v.(type) {
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return *v
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return formatFloat(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// formatTime formats t as RFC 3339 in the timezone offset zone, such as
+// "-08:00", or in UTC if zone is nil or invalid or utc is true.
+func formatTime(t *time.Time, zone *string, utc bool) string {
+	if t == nil {
+		return ""
+	}
+	if utc {
+		return t.UTC().Format(time.RFC3339)
+	}
+	return t.In(whoop.ParseOffset(zone)).Format(time.RFC3339)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func testSleep() whoop.Sleep {
+	start := time.Date(2022, 11, 27, 6, 30, 0, 0, time.UTC)
+	s := whoop.Sleep{ID: 1, UserID: 10, Start: &start, TimezoneOffset: whooptest.Ptr("-08:00"), ScoreState: whooptest.Ptr("SCORED")}
+	s.Score.StageSummary.TotalInBedTimeMilli = 30 * 60000
+	s.Score.StageSummary.TotalRemSleepTimeMilli = 90000
+	s.Score.SleepPerformancePercentage = 98.5
+	return s
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewSleepWriter(&buf, CSVOptions{Columns: []string{"id", "start", "score_stage_summary_total_in_bed_time_milli", "score_sleep_performance_percentage"}})
+	if err != nil {
+		t.Fatalf("NewSleepWriter(): expected nil error, got %v", err)
+	}
+	pending := testSleep()
+	pending.ID = 2
+	pending.ScoreState = whooptest.Ptr("PENDING_SCORE")
+	pending.TimezoneOffset = nil
+	w.Write(testSleep(), pending)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("CSVWriter.Flush(): expected nil error, got %v", err)
+	}
+
+	want := "id,start,score_stage_summary_total_in_bed_time_milli,score_sleep_performance_percentage\n" +
+		"1,2022-11-26T22:30:00-08:00,1800000,98.5\n" +
+		"2,2022-11-27T06:30:00Z,,\n"
+	if got := buf.String(); got != want {
+		t.Errorf("CSVWriter.Write(): got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestCSVWriter_units(t *testing.T) {
+	var buf bytes.Buffer
+	w, _ := NewSleepWriter(&buf, CSVOptions{
+		Columns:  []string{"start", "score_stage_summary_total_in_bed_time_milli", "score_stage_summary_total_rem_sleep_time_milli"},
+		Duration: Minutes,
+		UTC:      true,
+	})
+	w.Write(testSleep())
+	w.Flush()
+
+	want := "start,score_stage_summary_total_in_bed_time_minutes,score_stage_summary_total_rem_sleep_time_minutes\n" +
+		"2022-11-27T06:30:00Z,30,1.5\n"
+	if got := buf.String(); got != want {
+		t.Errorf("CSVWriter.Write(): got\n%v\nwant\n%v", got, want)
+	}
+
+	buf.Reset()
+	cw, _ := NewCycleWriter(&buf, CSVOptions{Columns: []string{"id", "score_kilojoule"}, Energy: Kilocalories})
+	c := whoop.Cycle{ID: 3, ScoreState: whooptest.Ptr("SCORED")}
+	c.Score.Kilojoule = 4184
+	cw.Write(c)
+	cw.Flush()
+	if got, want := buf.String(), "id,score_kilocalorie\n3,1000\n"; got != want {
+		t.Errorf("CSVWriter.Write(): got %q, want %q", got, want)
+	}
+}
+
+func TestCSVWriter_columns(t *testing.T) {
+	if _, err := NewWorkoutWriter(&bytes.Buffer{}, CSVOptions{Columns: []string{"nope"}}); err == nil {
+		t.Error("NewWorkoutWriter(): expected error for unknown column, got nil")
+	}
+
+	var buf bytes.Buffer
+	w, _ := NewRecoveryWriter(&buf, CSVOptions{})
+	if err := w.Flush(); err != nil {
+		t.Fatalf("CSVWriter.Flush(): expected nil error, got %v", err)
+	}
+	if got, want := buf.String(), strings.Join(w.Columns(), ",")+"\n"; got != want {
+		t.Errorf("CSVWriter.Flush(): expected header only, got %q, want %q", got, want)
+	}
+	for _, name := range []string{"cycle_id", "score_hrv_rmssd_milli", "score_skin_temp_celsius"} {
+		if !strings.Contains(buf.String(), name) {
+			t.Errorf("CSVWriter.Columns(): expected column %v in %v", name, w.Columns())
+		}
+	}
+}
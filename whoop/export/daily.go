@@ -0,0 +1,55 @@
+package export
+
+import (
+	"sort"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// DailySummary joins a physiological cycle with its recovery, the sleep
+// that recovery was computed from, and the workouts started during the cycle.
+type DailySummary struct {
+	Cycle    whoop.Cycle
+	Recovery *whoop.Recovery // Nil if the cycle has no recovery.
+	Sleep    *whoop.Sleep    // Nil if the cycle has no recovery, or its sleep is missing.
+	Workouts []whoop.Workout
+}
+
+// JoinDaily joins records into one DailySummary per cycle, sorted by
+// cycle start time. Recoveries are matched by CycleID, sleeps by the
+// recovery SleepID, and workouts by their start time falling within
+// the cycle. Workouts of an unfinished cycle are those started after
+// its start.
+func JoinDaily(cycles []whoop.Cycle, recoveries []whoop.Recovery, sleeps []whoop.Sleep, workouts []whoop.Workout) []DailySummary {
+	recoveryByCycle := make(map[int]*whoop.Recovery, len(recoveries))
+	for i := range recoveries {
+		recoveryByCycle[recoveries[i].CycleID] = &recoveries[i]
+	}
+	sleepByID := make(map[int]*whoop.Sleep, len(sleeps))
+	for i := range sleeps {
+		sleepByID[sleeps[i].ID] = &sleeps[i]
+	}
+
+	days := make([]DailySummary, len(cycles))
+	for i, c := range cycles {
+		d := DailySummary{Cycle: c, Recovery: recoveryByCycle[c.ID]}
+		if d.Recovery != nil {
+			d.Sleep = sleepByID[d.Recovery.SleepID]
+		}
+		for _, w := range workouts {
+			if w.Start == nil || c.Start == nil || w.Start.Before(*c.Start) {
+				continue
+			}
+			if c.End != nil && !w.Start.Before(*c.End) {
+				continue
+			}
+			d.Workouts = append(d.Workouts, w)
+		}
+		days[i] = d
+	}
+	sort.SliceStable(days, func(i, j int) bool {
+		a, b := days[i].Cycle.Start, days[j].Cycle.Start
+		return a != nil && (b == nil || a.Before(*b))
+	})
+	return days
+}
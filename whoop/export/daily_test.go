@@ -0,0 +1,51 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestJoinDaily(t *testing.T) {
+	day := time.Date(2022, 11, 27, 8, 0, 0, 0, time.UTC)
+	cycles := []whoop.Cycle{
+		{ID: 2, Start: whooptest.Ptr(day.AddDate(0, 0, 1))},
+		{ID: 1, Start: &day, End: whooptest.Ptr(day.AddDate(0, 0, 1))},
+	}
+	recoveries := []whoop.Recovery{{CycleID: 1, SleepID: 5, ScoreState: whooptest.Ptr("SCORED")}}
+	recoveries[0].Score.RecoveryScore = 66
+	sleeps := []whoop.Sleep{{ID: 5}}
+	workouts := []whoop.Workout{
+		{ID: 7, Start: whooptest.Ptr(day.Add(time.Hour))},
+		{ID: 8, Start: whooptest.Ptr(day.AddDate(0, 0, 1))},
+		{ID: 9, Start: whooptest.Ptr(day.Add(-time.Hour))},
+	}
+	workouts[0].Score.Kilojoule = 100
+	workouts[1].Score.Kilojoule = 50
+
+	days := JoinDaily(cycles, recoveries, sleeps, workouts)
+	if len(days) != 2 || days[0].Cycle.ID != 1 || days[1].Cycle.ID != 2 {
+		t.Fatalf("JoinDaily(): expected days sorted by start, got %+v", days)
+	}
+	if days[0].Recovery == nil || days[0].Sleep == nil || days[0].Sleep.ID != 5 {
+		t.Errorf("JoinDaily(): expected recovery and sleep joined, got %+v", days[0])
+	}
+	if len(days[0].Workouts) != 1 || days[0].Workouts[0].ID != 7 {
+		t.Errorf("JoinDaily(): expected workout 7 in cycle 1, got %+v", days[0].Workouts)
+	}
+	if days[1].Recovery != nil || len(days[1].Workouts) != 1 || days[1].Workouts[0].ID != 8 {
+		t.Errorf("JoinDaily(): expected workout 8 and no recovery in cycle 2, got %+v", days[1])
+	}
+
+	var buf bytes.Buffer
+	w, _ := NewDailySummaryWriter(&buf, CSVOptions{Columns: []string{"cycle_id", "recovery_score", "sleep_id", "workout_count", "workout_kilojoule"}})
+	w.Write(days...)
+	w.Flush()
+	want := "cycle_id,recovery_score,sleep_id,workout_count,workout_kilojoule\n1,66,5,1,100\n2,,,1,50\n"
+	if got := buf.String(); got != want {
+		t.Errorf("DailySummary CSV: got\n%v\nwant\n%v", got, want)
+	}
+}
@@ -0,0 +1,152 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Google Fit activity types of sleep sessions.
+const googleFitSleep = 72
+
+// GoogleFitActivityTypes maps WHOOP sport IDs to Google Fit activity types.
+// Sports without an equivalent are exported as "Other" (108).
+var GoogleFitActivityTypes = map[int]int{
+	0:   8,   // Running
+	1:   1,   // Biking
+	16:  11,  // Baseball
+	17:  12,  // Basketball
+	18:  53,  // Rowing
+	19:  26,  // Fencing
+	20:  36,  // Hockey
+	21:  27,  // Football (American)
+	22:  32,  // Golf
+	24:  36,  // Hockey
+	27:  55,  // Rugby
+	28:  59,  // Sailing
+	29:  67,  // Skiing (downhill)
+	30:  29,  // Football (Soccer)
+	31:  120, // Softball
+	32:  76,  // Squash
+	33:  82,  // Swimming
+	34:  87,  // Tennis
+	36:  89,  // Volleyball
+	37:  96,  // Water polo
+	39:  20,  // Boxing
+	42:  24,  // Dancing
+	43:  49,  // Pilates
+	44:  100, // Yoga
+	45:  97,  // Weightlifting
+	47:  66,  // Skiing (cross country)
+	48:  113, // CrossFit
+	51:  33,  // Gymnastics
+	52:  35,  // Hiking
+	53:  37,  // Horseback riding
+	55:  40,  // Kayaking
+	56:  44,  // Martial arts
+	57:  15,  // Mountain biking
+	59:  80,  // Strength training
+	60:  52,  // Rock climbing
+	61:  79,  // Stand-up paddleboarding
+	63:  7,   // Walking
+	64:  81,  // Surfing
+	65:  25,  // Elliptical
+	66:  78,  // Stair-climbing machine
+	70:  45,  // Meditation
+	73:  102, // Diving
+	83:  52,  // Rock climbing
+	84:  39,  // Jumping rope
+	85:  28,  // Football (Australian)
+	86:  61,  // Skateboarding
+	91:  73,  // Snowboarding
+	96:  114, // HIIT
+	97:  17,  // Spinning
+	98:  44,  // Martial arts
+	100: 23,  // Cricket
+	102: 62,  // Skating
+	103: 20,  // Boxing
+	105: 98,  // Wheelchair
+	126: 18,  // Biking (stationary)
+	127: 42,  // Kickboxing
+}
+
+const googleFitOther = 108
+
+type googleFitSessions struct {
+	Session []googleFitSession `json:"session"`
+}
+
+type googleFitSession struct {
+	ID                 string               `json:"id"`
+	Name               string               `json:"name"`
+	Description        string               `json:"description,omitempty"`
+	StartTimeMillis    string               `json:"startTimeMillis"`
+	EndTimeMillis      string               `json:"endTimeMillis"`
+	ModifiedTimeMillis string               `json:"modifiedTimeMillis,omitempty"`
+	Application        googleFitApplication `json:"application"`
+	ActivityType       int                  `json:"activityType"`
+}
+
+type googleFitApplication struct {
+	Name string `json:"name"`
+}
+
+// WriteGoogleFitSessions writes the sleeps and workouts of data as Google Fit
+// sessions, in the JSON format of the Fitness API sessions list response.
+// Sleeps are sleep sessions, and workouts are mapped to an activity type
+// using GoogleFitActivityTypes. Recoveries are ignored.
+func WriteGoogleFitSessions(w io.Writer, data HealthData) error {
+	out := googleFitSessions{Session: []googleFitSession{}}
+	for i := range data.Sleeps {
+		s := &data.Sleeps[i]
+		if !s.Scored() || s.Start == nil || s.End == nil {
+			continue
+		}
+		name := "Sleep"
+		if s.Nap {
+			name = "Nap"
+		}
+		out.Session = append(out.Session, googleFitSession{
+			ID:                 fmt.Sprintf("whoop-sleep-%v", s.ID),
+			Name:               name,
+			Description:        fmt.Sprintf("Sleep performance %v%%", formatFloat(s.Score.SleepPerformancePercentage)),
+			StartTimeMillis:    strconv.FormatInt(s.Start.UnixMilli(), 10),
+			EndTimeMillis:      strconv.FormatInt(s.End.UnixMilli(), 10),
+			ModifiedTimeMillis: modifiedMillis(s.UpdatedAt),
+			Application:        googleFitApplication{Name: appleHealthSource},
+			ActivityType:       googleFitSleep,
+		})
+	}
+	for i := range data.Workouts {
+		wo := &data.Workouts[i]
+		if !wo.Scored() || wo.Start == nil || wo.End == nil {
+			continue
+		}
+		activity, ok := GoogleFitActivityTypes[wo.SportID]
+		if !ok {
+			activity = googleFitOther
+		}
+		out.Session = append(out.Session, googleFitSession{
+			ID:                 fmt.Sprintf("whoop-workout-%v", wo.ID),
+			Name:               wo.Sport(),
+			Description:        fmt.Sprintf("Strain %v", formatFloat(wo.Score.Strain)),
+			StartTimeMillis:    strconv.FormatInt(wo.Start.UnixMilli(), 10),
+			EndTimeMillis:      strconv.FormatInt(wo.End.UnixMilli(), 10),
+			ModifiedTimeMillis: modifiedMillis(wo.UpdatedAt),
+			Application:        googleFitApplication{Name: appleHealthSource},
+			ActivityType:       activity,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func modifiedMillis(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return strconv.FormatInt(t.UnixMilli(), 10)
+}
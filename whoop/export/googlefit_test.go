@@ -0,0 +1,51 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestWriteGoogleFitSessions(t *testing.T) {
+	nap := testSleep()
+	end := nap.Start.Add(30 * time.Minute)
+	nap.End = &end
+	nap.Nap = true
+	unscored := testSleep()
+	unscored.End = &end
+	unscored.ScoreState = nil
+
+	var buf bytes.Buffer
+	err := WriteGoogleFitSessions(&buf, HealthData{
+		Sleeps:   []whoop.Sleep{nap, unscored},
+		Workouts: []whoop.Workout{testWorkout()},
+	})
+	if err != nil {
+		t.Fatalf("WriteGoogleFitSessions(): expected nil error, got %v", err)
+	}
+
+	var got struct {
+		Session []struct {
+			ID              string `json:"id"`
+			Name            string `json:"name"`
+			StartTimeMillis string `json:"startTimeMillis"`
+			EndTimeMillis   string `json:"endTimeMillis"`
+			ActivityType    int    `json:"activityType"`
+		} `json:"session"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("WriteGoogleFitSessions(): expected valid JSON, got %v", err)
+	}
+	if len(got.Session) != 2 {
+		t.Fatalf("WriteGoogleFitSessions(): expected 2 sessions, got %v", len(got.Session))
+	}
+	if s := got.Session[0]; s.ID != "whoop-sleep-1" || s.Name != "Nap" || s.ActivityType != 72 || s.StartTimeMillis != "1669530600000" || s.EndTimeMillis != "1669532400000" {
+		t.Errorf("WriteGoogleFitSessions(): got sleep session %+v", s)
+	}
+	if s := got.Session[1]; s.ID != "whoop-workout-7" || s.Name != "Running" || s.ActivityType != 8 {
+		t.Errorf("WriteGoogleFitSessions(): got workout session %+v", s)
+	}
+}
@@ -0,0 +1,25 @@
+package export
+
+import (
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// HealthData holds the records exported to other health apps by
+// WriteAppleHealth and WriteGoogleFitSessions. Records which are not
+// scored are skipped.
+type HealthData struct {
+	ExportDate time.Time // Time of the export. Defaults to the current time.
+
+	Recoveries []whoop.Recovery
+	Sleeps     []whoop.Sleep
+	Workouts   []whoop.Workout
+}
+
+func (h HealthData) exportDate() time.Time {
+	if h.ExportDate.IsZero() {
+		return time.Now()
+	}
+	return h.ExportDate
+}
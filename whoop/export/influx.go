@@ -0,0 +1,72 @@
+package export
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// InfluxWriter writes points in InfluxDB line protocol, one line per point
+// with nanosecond timestamps.
+//
+// Line protocol reference: https://docs.influxdata.com/influxdb/v2/reference/syntax/line-protocol/
+type InfluxWriter struct {
+	w *bufio.Writer
+}
+
+// NewInfluxWriter returns an InfluxWriter writing to w.
+func NewInfluxWriter(w io.Writer) *InfluxWriter {
+	return &InfluxWriter{w: bufio.NewWriter(w)}
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	keyEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+// WritePoints writes points. Fields with a NaN or infinite value, which
+// line protocol cannot represent, are skipped, as are points without any
+// other field.
+func (iw *InfluxWriter) WritePoints(points ...Point) error {
+	for _, p := range points {
+		var b strings.Builder
+		b.WriteString(measurementEscaper.Replace(p.Measurement))
+		for _, t := range p.Tags {
+			if t.Value == "" {
+				continue // Empty tag values are invalid.
+			}
+			b.WriteByte(',')
+			b.WriteString(keyEscaper.Replace(t.Key))
+			b.WriteByte('=')
+			b.WriteString(keyEscaper.Replace(t.Value))
+		}
+		sep := byte(' ')
+		for _, f := range p.Fields {
+			if math.IsNaN(f.Value) || math.IsInf(f.Value, 0) {
+				continue
+			}
+			b.WriteByte(sep)
+			b.WriteString(keyEscaper.Replace(f.Key))
+			b.WriteByte('=')
+			b.WriteString(strconv.FormatFloat(f.Value, 'f', -1, 64))
+			sep = ','
+		}
+		if sep == ' ' {
+			continue
+		}
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatInt(p.Time.UnixNano(), 10))
+		b.WriteByte('\n')
+		if _, err := iw.w.WriteString(b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (iw *InfluxWriter) Flush() error {
+	return iw.w.Flush()
+}
@@ -0,0 +1,31 @@
+package export
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestInfluxWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewInfluxWriter(&buf)
+	ts := time.Unix(1669536000, 5)
+	err := w.WritePoints(
+		Point{
+			Measurement: "whoop workout",
+			Tags:        []Tag{{"sport", "Track & Field, Sprint"}, {"team", ""}, {"user_id", "10"}},
+			Fields:      []Field{{"strain", 12.25}, {"bad", math.NaN()}, {"zone one", 1e6}},
+			Time:        ts,
+		},
+		Point{Measurement: "empty", Fields: []Field{{"x", math.Inf(1)}}, Time: ts},
+	)
+	if err != nil {
+		t.Fatalf("InfluxWriter.WritePoints(): expected nil error, got %v", err)
+	}
+	w.Flush()
+	want := `whoop\ workout,sport=Track\ &\ Field\,\ Sprint,user_id=10 strain=12.25,zone\ one=1000000 1669536000000000005` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("InfluxWriter.WritePoints(): got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,199 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// RecordType discriminates the records of a JSON Lines file.
+type RecordType string
+
+// Record types of a JSON Lines file.
+const (
+	TypeCycle           RecordType = "cycle"
+	TypeRecovery        RecordType = "recovery"
+	TypeSleep           RecordType = "sleep"
+	TypeWorkout         RecordType = "workout"
+	TypeUserProfile     RecordType = "user_profile"
+	TypeBodyMeasurement RecordType = "body_measurement"
+)
+
+// Line is a single line of a JSON Lines file: a record and its type.
+type Line struct {
+	Type RecordType      `json:"type"`
+	Data json.RawMessage `json:"data"` // The record, as returned by the API.
+}
+
+// Decode decodes the record of l into a *whoop.Cycle, *whoop.Recovery,
+// *whoop.Sleep, *whoop.Workout, *whoop.UserProfile or *whoop.BodyMeasurement
+// depending on its type.
+func (l Line) Decode() (any, error) {
+	var v any
+	switch l.Type {
+	case TypeCycle:
+		v = &whoop.Cycle{}
+	case TypeRecovery:
+		v = &whoop.Recovery{}
+	case TypeSleep:
+		v = &whoop.Sleep{}
+	case TypeWorkout:
+		v = &whoop.Workout{}
+	case TypeUserProfile:
+		v = &whoop.UserProfile{}
+	case TypeBodyMeasurement:
+		v = &whoop.BodyMeasurement{}
+	default:
+		return nil, fmt.Errorf("export: unknown record type %q", l.Type)
+	}
+	if err := json.Unmarshal(l.Data, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// typeOf returns the record type of v.
+func typeOf(v any) (RecordType, bool) {
+	switch v.(type) {
+	case whoop.Cycle, *whoop.Cycle:
+		return TypeCycle, true
+	case whoop.Recovery, *whoop.Recovery:
+		return TypeRecovery, true
+	case whoop.Sleep, *whoop.Sleep:
+		return TypeSleep, true
+	case whoop.Workout, *whoop.Workout:
+		return TypeWorkout, true
+	case whoop.UserProfile, *whoop.UserProfile:
+		return TypeUserProfile, true
+	case whoop.BodyMeasurement, *whoop.BodyMeasurement:
+		return TypeBodyMeasurement, true
+	}
+	return "", false
+}
+
+// JSONLWriter writes records as JSON Lines, one record per line wrapped
+// in a Line with its type.
+type JSONLWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLWriter returns a JSONLWriter writing to w.
+func NewJSONLWriter(w io.Writer) *JSONLWriter {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	return &JSONLWriter{w: bw, enc: enc}
+}
+
+// WriteRecord writes a line holding v, which must be one of the record
+// types returned by Line.Decode, or a value of such a type.
+func (w *JSONLWriter) WriteRecord(v any) error {
+	t, ok := typeOf(v)
+	if !ok {
+		return fmt.Errorf("export: unsupported record type %T", v)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.WriteLine(Line{Type: t, Data: data})
+}
+
+// WriteLine writes l as is. It can be used to archive raw API responses,
+// or to copy lines read by a JSONLReader.
+func (w *JSONLWriter) WriteLine(l Line) error {
+	return w.enc.Encode(l)
+}
+
+// Flush writes any buffered data to the underlying io.Writer.
+func (w *JSONLWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// JSONLRecords returns a RecordWriter of records of type T writing to w,
+// which can be used to stream records from the API.
+func JSONLRecords[T any](w *JSONLWriter) RecordWriter[T] {
+	return jsonlRecords[T]{w}
+}
+
+type jsonlRecords[T any] struct {
+	w *JSONLWriter
+}
+
+func (j jsonlRecords[T]) Write(records ...T) error {
+	for i := range records {
+		if err := j.w.WriteRecord(&records[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j jsonlRecords[T]) Flush() error {
+	return j.w.Flush()
+}
+
+// JSONLReader reads the lines of a JSON Lines file.
+type JSONLReader struct {
+	r    *bufio.Reader
+	line int // Number of the last line read, starting at 1.
+}
+
+// NewJSONLReader returns a JSONLReader reading from r.
+func NewJSONLReader(r io.Reader) *JSONLReader {
+	return &JSONLReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next line. Each line must hold exactly one JSON
+// object; blank lines are skipped. It returns io.EOF when there are no
+// more lines.
+func (r *JSONLReader) Next() (Line, error) {
+	var l Line
+	for {
+		data, err := r.r.ReadBytes('\n')
+		if len(data) == 0 && errors.Is(err, io.EOF) {
+			return l, io.EOF
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return l, fmt.Errorf("export: reading line %v: %w", r.line+1, err)
+		}
+		r.line++
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(data, &l); err != nil {
+			return l, fmt.Errorf("export: reading line %v: %w", r.line, err)
+		}
+		if l.Type == "" {
+			return l, fmt.Errorf("export: reading line %v: missing record type", r.line)
+		}
+		return l, nil
+	}
+}
+
+// ReadAll reads every remaining line and decodes its record.
+// See Line.Decode for the types of the returned records.
+func (r *JSONLReader) ReadAll() ([]any, error) {
+	var records []any
+	for {
+		l, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		v, err := l.Decode()
+		if err != nil {
+			return records, fmt.Errorf("export: decoding line %v: %w", r.line, err)
+		}
+		records = append(records, v)
+	}
+}
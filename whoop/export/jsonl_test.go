@@ -0,0 +1,105 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestJSONL_roundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf)
+
+	s := testSleep()
+	if err := w.WriteRecord(s); err != nil {
+		t.Fatalf("JSONLWriter.WriteRecord(): expected nil error, got %v", err)
+	}
+	if err := w.WriteRecord(&whoop.UserProfile{ID: 10}); err != nil {
+		t.Fatalf("JSONLWriter.WriteRecord(): expected nil error, got %v", err)
+	}
+	raw := json.RawMessage(`{"id":3,"brand_new_field":{"a":1}}`)
+	w.WriteLine(Line{Type: TypeCycle, Data: raw})
+	if err := w.WriteRecord(42); err == nil {
+		t.Error("JSONLWriter.WriteRecord(): expected error for unsupported type, got nil")
+	}
+	w.Flush()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], `{"type":"sleep","data":{`) {
+		t.Fatalf("JSONLWriter: expected 3 lines, got %q", lines)
+	}
+	if got, want := lines[2], `{"type":"cycle","data":{"id":3,"brand_new_field":{"a":1}}}`; got != want {
+		t.Errorf("JSONLWriter.WriteLine(): got %v, want %v", got, want)
+	}
+
+	r := NewJSONLReader(strings.NewReader(buf.String()))
+	l, err := r.Next()
+	if err != nil || l.Type != TypeSleep {
+		t.Fatalf("JSONLReader.Next(): got %v %v, want sleep line", l, err)
+	}
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("JSONLReader.ReadAll(): expected nil error, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("JSONLReader.ReadAll(): expected 2 records, got %v", len(records))
+	}
+	if p, ok := records[0].(*whoop.UserProfile); !ok || p.ID != 10 {
+		t.Errorf("JSONLReader.ReadAll(): expected user profile 10, got %#v", records[0])
+	}
+	if c, ok := records[1].(*whoop.Cycle); !ok || c.ID != 3 {
+		t.Errorf("JSONLReader.ReadAll(): expected cycle 3, got %#v", records[1])
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("JSONLReader.Next(): expected io.EOF, got %v", err)
+	}
+
+	// Unknown fields survive a round trip through WriteRecord.
+	buf.Reset()
+	w.WriteRecord(records[1])
+	w.Flush()
+	if got, want := buf.String(), `"brand_new_field":{"a":1}`; !strings.Contains(got, want) {
+		t.Errorf("JSONLWriter.WriteRecord(): got %v, want it to contain %v", got, want)
+	}
+
+	first, _ := NewJSONLReader(strings.NewReader(lines[0])).Next()
+	v, _ := first.Decode()
+	if got := v.(*whoop.Sleep); got.Score.SleepPerformancePercentage != 98.5 || *got.TimezoneOffset != "-08:00" {
+		t.Errorf("Line.Decode(): got %+v, want %+v", got, s)
+	}
+}
+
+func TestJSONLReader_errors(t *testing.T) {
+	cycle := `{"type":"cycle","data":{"id":1}}`
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{`{"data":{}}`, "line 1: missing record type"},
+		{`{"type":"cycle","data":`, "line 1: unexpected end of JSON input"},
+		{`{"type":"nope","data":{}}`, `line 1: export: unknown record type "nope"`},
+		{cycle + "\n\n" + cycle + " " + cycle + "\n", "line 3: invalid character"},
+		{cycle + "\n{\"type\":\"cycle\",\n\"data\":{}}\n", "line 2: unexpected end of JSON input"},
+	}
+
+	for _, test := range testCases {
+		_, err := NewJSONLReader(strings.NewReader(test.input)).ReadAll()
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("JSONLReader.ReadAll(%q): got error %v, want %v", test.input, err, test.want)
+		}
+	}
+}
+
+func TestJSONLRecords(t *testing.T) {
+	var buf bytes.Buffer
+	w := JSONLRecords[whoop.Recovery](NewJSONLWriter(&buf))
+	w.Write(whoop.Recovery{CycleID: 1}, whoop.Recovery{CycleID: 2})
+	w.Flush()
+	if got := strings.Count(buf.String(), `"type":"recovery"`); got != 2 {
+		t.Errorf("JSONLRecords(): expected 2 recovery lines, got %q", buf.String())
+	}
+}
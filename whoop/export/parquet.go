@@ -0,0 +1,290 @@
+package export
+
+import (
+	"io"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// Compression is a Parquet compression codec.
+type Compression int
+
+// Parquet compression codecs.
+const (
+	Snappy Compression = iota
+	Zstd
+	Gzip
+	Uncompressed
+)
+
+var codecs = map[Compression]compress.Codec{
+	Snappy:       &parquet.Snappy,
+	Zstd:         &parquet.Zstd,
+	Gzip:         &parquet.Gzip,
+	Uncompressed: &parquet.Uncompressed,
+}
+
+const defaultRowGroupSize = 100_000
+
+// ParquetOptions configures a ParquetWriter.
+type ParquetOptions struct {
+	// RowGroupSize is the maximum number of rows per row group.
+	// Defaults to 100,000.
+	RowGroupSize int64
+
+	// Compression is the codec used to compress column pages.
+	// Defaults to Snappy.
+	Compression Compression
+}
+
+// ParquetWriter writes records of type T to a Parquet file, converting them
+// to rows of type R. Times are stored as UTC timestamps in milliseconds,
+// next to the record's timezone offset, and score columns are null for
+// records which are not scored.
+//
+// Close must be called to write the file footer.
+type ParquetWriter[T, R any] struct {
+	w     *parquet.GenericWriter[R]
+	toRow func(r *T) R
+	rows  []R
+}
+
+// NewCycleParquetWriter returns a ParquetWriter of cycles writing to w.
+func NewCycleParquetWriter(w io.Writer, opts ParquetOptions) *ParquetWriter[whoop.Cycle, CycleRow] {
+	return newParquetWriter(w, opts, CycleRowOf)
+}
+
+// NewRecoveryParquetWriter returns a ParquetWriter of recoveries writing to w.
+func NewRecoveryParquetWriter(w io.Writer, opts ParquetOptions) *ParquetWriter[whoop.Recovery, RecoveryRow] {
+	return newParquetWriter(w, opts, RecoveryRowOf)
+}
+
+// NewSleepParquetWriter returns a ParquetWriter of sleeps writing to w.
+func NewSleepParquetWriter(w io.Writer, opts ParquetOptions) *ParquetWriter[whoop.Sleep, SleepRow] {
+	return newParquetWriter(w, opts, SleepRowOf)
+}
+
+// NewWorkoutParquetWriter returns a ParquetWriter of workouts writing to w.
+func NewWorkoutParquetWriter(w io.Writer, opts ParquetOptions) *ParquetWriter[whoop.Workout, WorkoutRow] {
+	return newParquetWriter(w, opts, WorkoutRowOf)
+}
+
+func newParquetWriter[T, R any](w io.Writer, opts ParquetOptions, toRow func(r *T) R) *ParquetWriter[T, R] {
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = defaultRowGroupSize
+	}
+	codec, ok := codecs[opts.Compression]
+	if !ok {
+		codec = &parquet.Snappy
+	}
+	return &ParquetWriter[T, R]{
+		w:     parquet.NewGenericWriter[R](w, parquet.MaxRowsPerRowGroup(opts.RowGroupSize), parquet.Compression(codec)),
+		toRow: toRow,
+	}
+}
+
+// Schema returns the Parquet schema of the file.
+func (w *ParquetWriter[T, R]) Schema() *parquet.Schema {
+	return w.w.Schema()
+}
+
+// Write writes records as Parquet rows.
+func (w *ParquetWriter[T, R]) Write(records ...T) error {
+	w.rows = w.rows[:0]
+	for i := range records {
+		w.rows = append(w.rows, w.toRow(&records[i]))
+	}
+	_, err := w.w.Write(w.rows)
+	return err
+}
+
+// Flush flushes buffered rows to the current row group.
+func (w *ParquetWriter[T, R]) Flush() error {
+	return w.w.Flush()
+}
+
+// Close writes any buffered rows and the file footer.
+// It doesn't close the underlying io.Writer.
+func (w *ParquetWriter[T, R]) Close() error {
+	return w.w.Close()
+}
+
+// CycleRow is the Parquet row of a whoop.Cycle.
+type CycleRow struct {
+	ID             int64    `parquet:"id"`
+	UserID         int64    `parquet:"user_id"`
+	CreatedAt      int64    `parquet:"created_at,optional,timestamp(millisecond)"`
+	UpdatedAt      int64    `parquet:"updated_at,optional,timestamp(millisecond)"`
+	Start          int64    `parquet:"start,optional,timestamp(millisecond)"`
+	End            int64    `parquet:"end,optional,timestamp(millisecond)"`
+	TimezoneOffset string   `parquet:"timezone_offset,optional,dict"`
+	ScoreState     string   `parquet:"score_state,optional,dict"`
+	Strain         *float64 `parquet:"strain,optional"`
+	Kilojoule      *float64 `parquet:"kilojoule,optional"`
+	AverageHR      *float64 `parquet:"average_heart_rate,optional"`
+	MaxHR          *float64 `parquet:"max_heart_rate,optional"`
+}
+
+// CycleRowOf returns the Parquet row of c.
+func CycleRowOf(c *whoop.Cycle) CycleRow {
+	row := CycleRow{
+		ID: int64(c.ID), UserID: int64(c.UserID),
+		CreatedAt: millis(c.CreatedAt), UpdatedAt: millis(c.UpdatedAt), Start: millis(c.Start), End: millis(c.End),
+		TimezoneOffset: str(c.TimezoneOffset), ScoreState: str(c.ScoreState),
+	}
+	if c.Scored() {
+		row.Strain, row.Kilojoule = &c.Score.Strain, &c.Score.Kilojoule
+		row.AverageHR, row.MaxHR = &c.Score.AverageHeartRate, &c.Score.MaxHeartRate
+	}
+	return row
+}
+
+// RecoveryRow is the Parquet row of a whoop.Recovery.
+type RecoveryRow struct {
+	CycleID          int64    `parquet:"cycle_id"`
+	SleepID          int64    `parquet:"sleep_id"`
+	UserID           int64    `parquet:"user_id"`
+	CreatedAt        int64    `parquet:"created_at,optional,timestamp(millisecond)"`
+	UpdatedAt        int64    `parquet:"updated_at,optional,timestamp(millisecond)"`
+	ScoreState       string   `parquet:"score_state,optional,dict"`
+	UserCalibrating  *bool    `parquet:"user_calibrating,optional"`
+	RecoveryScore    *float64 `parquet:"recovery_score,optional"`
+	RestingHeartRate *float64 `parquet:"resting_heart_rate,optional"`
+	HrvRmssdMilli    *float64 `parquet:"hrv_rmssd_milli,optional"`
+	Spo2Percentage   *float64 `parquet:"spo2_percentage,optional"`
+	SkinTempCelsius  *float64 `parquet:"skin_temp_celsius,optional"`
+}
+
+// RecoveryRowOf returns the Parquet row of r.
+func RecoveryRowOf(r *whoop.Recovery) RecoveryRow {
+	row := RecoveryRow{
+		CycleID: int64(r.CycleID), SleepID: int64(r.SleepID), UserID: int64(r.UserID),
+		CreatedAt: millis(r.CreatedAt), UpdatedAt: millis(r.UpdatedAt), ScoreState: str(r.ScoreState),
+	}
+	if r.Scored() {
+		s := &r.Score
+		row.UserCalibrating, row.RecoveryScore, row.RestingHeartRate = &s.UserCalibrating, &s.RecoveryScore, &s.RestingHeartRate
+		row.HrvRmssdMilli, row.Spo2Percentage, row.SkinTempCelsius = &s.HrvRmssdMilli, &s.Spo2Percentage, &s.SkinTempCelsius
+	}
+	return row
+}
+
+// SleepRow is the Parquet row of a whoop.Sleep.
+type SleepRow struct {
+	ID                          int64    `parquet:"id"`
+	UserID                      int64    `parquet:"user_id"`
+	CreatedAt                   int64    `parquet:"created_at,optional,timestamp(millisecond)"`
+	UpdatedAt                   int64    `parquet:"updated_at,optional,timestamp(millisecond)"`
+	Start                       int64    `parquet:"start,optional,timestamp(millisecond)"`
+	End                         int64    `parquet:"end,optional,timestamp(millisecond)"`
+	TimezoneOffset              string   `parquet:"timezone_offset,optional,dict"`
+	Nap                         bool     `parquet:"nap"`
+	ScoreState                  string   `parquet:"score_state,optional,dict"`
+	TotalInBedTimeMilli         *int64   `parquet:"total_in_bed_time_milli,optional"`
+	TotalAwakeTimeMilli         *int64   `parquet:"total_awake_time_milli,optional"`
+	TotalNoDataTimeMilli        *int64   `parquet:"total_no_data_time_milli,optional"`
+	TotalLightSleepTimeMilli    *int64   `parquet:"total_light_sleep_time_milli,optional"`
+	TotalSlowWaveSleepTimeMilli *int64   `parquet:"total_slow_wave_sleep_time_milli,optional"`
+	TotalRemSleepTimeMilli      *int64   `parquet:"total_rem_sleep_time_milli,optional"`
+	SleepCycleCount             *int64   `parquet:"sleep_cycle_count,optional"`
+	DisturbanceCount            *int64   `parquet:"disturbance_count,optional"`
+	BaselineMilli               *int64   `parquet:"baseline_milli,optional"`
+	NeedFromSleepDebtMilli      *int64   `parquet:"need_from_sleep_debt_milli,optional"`
+	NeedFromRecentStrainMilli   *int64   `parquet:"need_from_recent_strain_milli,optional"`
+	NeedFromRecentNapMilli      *int64   `parquet:"need_from_recent_nap_milli,optional"`
+	RespiratoryRate             *float64 `parquet:"respiratory_rate,optional"`
+	SleepPerformancePercentage  *float64 `parquet:"sleep_performance_percentage,optional"`
+	SleepConsistencyPercentage  *float64 `parquet:"sleep_consistency_percentage,optional"`
+	SleepEfficiencyPercentage   *float64 `parquet:"sleep_efficiency_percentage,optional"`
+}
+
+// SleepRowOf returns the Parquet row of s.
+func SleepRowOf(s *whoop.Sleep) SleepRow {
+	row := SleepRow{
+		ID: int64(s.ID), UserID: int64(s.UserID),
+		CreatedAt: millis(s.CreatedAt), UpdatedAt: millis(s.UpdatedAt), Start: millis(s.Start), End: millis(s.End),
+		TimezoneOffset: str(s.TimezoneOffset), Nap: s.Nap, ScoreState: str(s.ScoreState),
+	}
+	if s.Scored() {
+		st, need := s.Score.StageSummary, s.Score.SleepNeeded
+		row.TotalInBedTimeMilli, row.TotalAwakeTimeMilli = i64(st.TotalInBedTimeMilli), i64(st.TotalAwakeTimeMilli)
+		row.TotalNoDataTimeMilli, row.TotalLightSleepTimeMilli = i64(st.TotalNoDataTimeMilli), i64(st.TotalLightSleepTimeMilli)
+		row.TotalSlowWaveSleepTimeMilli, row.TotalRemSleepTimeMilli = i64(st.TotalSlowWaveSleepTimeMilli), i64(st.TotalRemSleepTimeMilli)
+		row.SleepCycleCount, row.DisturbanceCount = i64(st.SleepCycleCount), i64(st.DisturbanceCount)
+		row.BaselineMilli, row.NeedFromSleepDebtMilli = i64(need.BaselineMilli), i64(need.NeedFromSleepDebtMilli)
+		row.NeedFromRecentStrainMilli, row.NeedFromRecentNapMilli = i64(need.NeedFromRecentStrainMilli), i64(need.NeedFromRecentNapMilli)
+		row.RespiratoryRate, row.SleepPerformancePercentage = &s.Score.RespiratoryRate, &s.Score.SleepPerformancePercentage
+		row.SleepConsistencyPercentage, row.SleepEfficiencyPercentage = &s.Score.SleepConsistencyPercentage, &s.Score.SleepEfficiencyPercentage
+	}
+	return row
+}
+
+// WorkoutRow is the Parquet row of a whoop.Workout.
+type WorkoutRow struct {
+	ID                  int64    `parquet:"id"`
+	UserID              int64    `parquet:"user_id"`
+	CreatedAt           int64    `parquet:"created_at,optional,timestamp(millisecond)"`
+	UpdatedAt           int64    `parquet:"updated_at,optional,timestamp(millisecond)"`
+	Start               int64    `parquet:"start,optional,timestamp(millisecond)"`
+	End                 int64    `parquet:"end,optional,timestamp(millisecond)"`
+	TimezoneOffset      string   `parquet:"timezone_offset,optional,dict"`
+	SportID             int64    `parquet:"sport_id"`
+	SportName           string   `parquet:"sport_name,optional,dict"`
+	ScoreState          string   `parquet:"score_state,optional,dict"`
+	Strain              *float64 `parquet:"strain,optional"`
+	AverageHeartRate    *int64   `parquet:"average_heart_rate,optional"`
+	MaxHeartRate        *int64   `parquet:"max_heart_rate,optional"`
+	Kilojoule           *float64 `parquet:"kilojoule,optional"`
+	PercentRecorded     *float64 `parquet:"percent_recorded,optional"`
+	DistanceMeter       *float64 `parquet:"distance_meter,optional"`
+	AltitudeGainMeter   *float64 `parquet:"altitude_gain_meter,optional"`
+	AltitudeChangeMeter *float64 `parquet:"altitude_change_meter,optional"`
+	ZoneZeroMilli       *int64   `parquet:"zone_zero_milli,optional"`
+	ZoneOneMilli        *int64   `parquet:"zone_one_milli,optional"`
+	ZoneTwoMilli        *int64   `parquet:"zone_two_milli,optional"`
+	ZoneThreeMilli      *int64   `parquet:"zone_three_milli,optional"`
+	ZoneFourMilli       *int64   `parquet:"zone_four_milli,optional"`
+	ZoneFiveMilli       *int64   `parquet:"zone_five_milli,optional"`
+}
+
+// WorkoutRowOf returns the Parquet row of w.
+func WorkoutRowOf(w *whoop.Workout) WorkoutRow {
+	row := WorkoutRow{
+		ID: int64(w.ID), UserID: int64(w.UserID),
+		CreatedAt: millis(w.CreatedAt), UpdatedAt: millis(w.UpdatedAt), Start: millis(w.Start), End: millis(w.End),
+		TimezoneOffset: str(w.TimezoneOffset), SportID: int64(w.SportID), SportName: str(w.SportName), ScoreState: str(w.ScoreState),
+	}
+	if w.Scored() {
+		s, z := &w.Score, w.Score.ZoneDuration
+		row.Strain, row.AverageHeartRate, row.MaxHeartRate = &s.Strain, i64(s.AverageHeartRate), i64(s.MaxHeartRate)
+		row.Kilojoule, row.PercentRecorded, row.DistanceMeter = &s.Kilojoule, &s.PercentRecorded, &s.DistanceMeter
+		row.AltitudeGainMeter, row.AltitudeChangeMeter = &s.AltitudeGainMeter, &s.AltitudeChangeMeter
+		row.ZoneZeroMilli, row.ZoneOneMilli, row.ZoneTwoMilli = i64(z.ZoneZeroMilli), i64(z.ZoneOneMilli), i64(z.ZoneTwoMilli)
+		row.ZoneThreeMilli, row.ZoneFourMilli, row.ZoneFiveMilli = i64(z.ZoneThreeMilli), i64(z.ZoneFourMilli), i64(z.ZoneFiveMilli)
+	}
+	return row
+}
+
+// millis returns t as milliseconds since the Unix epoch, or 0, which is
+// stored as null, if t is nil.
+func millis(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+func str(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func i64(v int) *int64 {
+	n := int64(v)
+	return &n
+}
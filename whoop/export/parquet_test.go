@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/parquet-go/parquet-go"
+)
+
+func TestParquetWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWorkoutParquetWriter(&buf, ParquetOptions{RowGroupSize: 1, Compression: Zstd})
+
+	start := time.Date(2022, 11, 27, 6, 30, 0, 0, time.UTC)
+	scored := whoop.Workout{ID: 1, UserID: 10, Start: &start, TimezoneOffset: whooptest.Ptr("-08:00"), SportName: whooptest.Ptr("Running"), ScoreState: whooptest.Ptr("SCORED")}
+	scored.Score.Strain = 8.2
+	scored.Score.ZoneDuration.ZoneTwoMilli = 600000
+	pending := whoop.Workout{ID: 2, UserID: 10, ScoreState: whooptest.Ptr("PENDING_SCORE")}
+	if err := w.Write(scored, pending); err != nil {
+		t.Fatalf("ParquetWriter.Write(): expected nil error, got %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("ParquetWriter.Close(): expected nil error, got %v", err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("parquet.OpenFile(): expected nil error, got %v", err)
+	}
+	if got := len(f.RowGroups()); got != 2 {
+		t.Errorf("ParquetWriter: expected 2 row groups, got %v", got)
+	}
+	schema := f.Schema().String()
+	for _, want := range []string{"optional int64 start (TIMESTAMP(isAdjustedToUTC=true,unit=MILLIS))", "optional double strain", "optional int64 zone_two_milli"} {
+		if !strings.Contains(schema, want) {
+			t.Errorf("ParquetWriter: expected schema to contain %q, got %v", want, schema)
+		}
+	}
+
+	rows, err := parquet.Read[WorkoutRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("parquet.Read(): expected nil error, got %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("parquet.Read(): expected 2 rows, got %v", len(rows))
+	}
+	if rows[0].Start != start.UnixMilli() || *rows[0].Strain != 8.2 || *rows[0].ZoneTwoMilli != 600000 || rows[0].SportName != "Running" {
+		t.Errorf("parquet.Read(): got %+v, want %+v", rows[0], WorkoutRowOf(&scored))
+	}
+	if rows[1].Strain != nil || rows[1].ZoneTwoMilli != nil || rows[1].Start != 0 {
+		t.Errorf("parquet.Read(): expected null score and start for pending workout, got %+v", rows[1])
+	}
+}
+
+func TestSleepRowOf(t *testing.T) {
+	s := testSleep()
+	row := SleepRowOf(&s)
+	if row.TotalInBedTimeMilli == nil || *row.TotalInBedTimeMilli != 1800000 || *row.SleepPerformancePercentage != 98.5 {
+		t.Errorf("SleepRowOf(): got %+v", row)
+	}
+	if row.TimezoneOffset != "-08:00" || row.Start != s.Start.UnixMilli() {
+		t.Errorf("SleepRowOf(): got %+v", row)
+	}
+}
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// Measurements of the points returned by CyclePoint, RecoveryPoint,
+// SleepPoint and WorkoutPoint.
+const (
+	CycleMeasurement    = "whoop_cycle"
+	RecoveryMeasurement = "whoop_recovery"
+	SleepMeasurement    = "whoop_sleep"
+	WorkoutMeasurement  = "whoop_workout"
+)
+
+// Point is a set of scored metrics of a record at a point in time, tagged
+// by user ID and, for workouts, sport.
+type Point struct {
+	Measurement string
+	Tags        []Tag   // Sorted by key.
+	Fields      []Field // One per metric.
+	Time        time.Time
+}
+
+// Tag is a key/value pair identifying the series of a Point.
+type Tag struct {
+	Key, Value string
+}
+
+// Field is a metric of a Point.
+type Field struct {
+	Key   string
+	Value float64
+}
+
+// CyclePoint returns the strain, kilojoule and heart rate metrics of c,
+// timestamped at the start of the cycle. It returns false if c is not
+// scored.
+func CyclePoint(c *whoop.Cycle) (Point, bool) {
+	if !c.Scored() || c.Start == nil {
+		return Point{}, false
+	}
+	return Point{
+		Measurement: CycleMeasurement,
+		Tags:        userTags(c.UserID),
+		Fields: []Field{
+			{"strain", c.Score.Strain},
+			{"kilojoule", c.Score.Kilojoule},
+			{"average_heart_rate", c.Score.AverageHeartRate},
+			{"max_heart_rate", c.Score.MaxHeartRate},
+		},
+		Time: *c.Start,
+	}, true
+}
+
+// RecoveryPoint returns the recovery score, HRV, resting heart rate, SpO2
+// and skin temperature metrics of r, timestamped at the time the recovery
+// was created. It returns false if r is not scored. SpO2 and skin
+// temperature are left out while the member is calibrating or when they
+// are zero, which means they were not measured.
+func RecoveryPoint(r *whoop.Recovery) (Point, bool) {
+	if !r.Scored() || r.CreatedAt == nil {
+		return Point{}, false
+	}
+	fields := []Field{
+		{"score", r.Score.RecoveryScore},
+		{"hrv_rmssd_milli", r.Score.HrvRmssdMilli},
+		{"resting_heart_rate", r.Score.RestingHeartRate},
+	}
+	if !r.Score.UserCalibrating {
+		if r.Score.Spo2Percentage != 0 {
+			fields = append(fields, Field{"spo2_percentage", r.Score.Spo2Percentage})
+		}
+		if r.Score.SkinTempCelsius != 0 {
+			fields = append(fields, Field{"skin_temp_celsius", r.Score.SkinTempCelsius})
+		}
+	}
+	return Point{
+		Measurement: RecoveryMeasurement,
+		Tags:        userTags(r.UserID),
+		Fields:      fields,
+		Time:        *r.CreatedAt,
+	}, true
+}
+
+// SleepPoint returns the performance, efficiency, consistency and
+// respiratory rate metrics of s, timestamped at the start of the sleep.
+// It returns false if s is not scored.
+func SleepPoint(s *whoop.Sleep) (Point, bool) {
+	if !s.Scored() || s.Start == nil {
+		return Point{}, false
+	}
+	return Point{
+		Measurement: SleepMeasurement,
+		Tags:        userTags(s.UserID),
+		Fields: []Field{
+			{"performance_percentage", s.Score.SleepPerformancePercentage},
+			{"efficiency_percentage", s.Score.SleepEfficiencyPercentage},
+			{"consistency_percentage", s.Score.SleepConsistencyPercentage},
+			{"respiratory_rate", s.Score.RespiratoryRate},
+		},
+		Time: *s.Start,
+	}, true
+}
+
+// WorkoutPoint returns the strain, kilojoule, heart rate and zone duration
+// metrics of w, tagged with its sport and timestamped at the start of the
+// workout. It returns false if w is not scored.
+func WorkoutPoint(w *whoop.Workout) (Point, bool) {
+	if !w.Scored() || w.Start == nil {
+		return Point{}, false
+	}
+	z := w.Score.ZoneDuration
+	return Point{
+		Measurement: WorkoutMeasurement,
+		Tags:        []Tag{{"sport", w.Sport()}, {"user_id", strconv.Itoa(w.UserID)}},
+		Fields: []Field{
+			{"strain", w.Score.Strain},
+			{"kilojoule", w.Score.Kilojoule},
+			{"average_heart_rate", float64(w.Score.AverageHeartRate)},
+			{"max_heart_rate", float64(w.Score.MaxHeartRate)},
+			{"zone_zero_milli", float64(z.ZoneZeroMilli)},
+			{"zone_one_milli", float64(z.ZoneOneMilli)},
+			{"zone_two_milli", float64(z.ZoneTwoMilli)},
+			{"zone_three_milli", float64(z.ZoneThreeMilli)},
+			{"zone_four_milli", float64(z.ZoneFourMilli)},
+			{"zone_five_milli", float64(z.ZoneFiveMilli)},
+		},
+		Time: *w.Start,
+	}, true
+}
+
+func userTags(userID int) []Tag {
+	return []Tag{{"user_id", strconv.Itoa(userID)}}
+}
+
+// PointWriter is a writer of points, such as an InfluxWriter or a
+// RemoteWriteEncoder.
+type PointWriter interface {
+	WritePoints(points ...Point) error
+	Flush() error
+}
+
+// PointRecords returns a RecordWriter of records of type T converting them
+// to points with point and writing them to w, which can be used to stream
+// records from the API. Records for which point returns false are skipped.
+func PointRecords[T any](w PointWriter, point func(*T) (Point, bool)) RecordWriter[T] {
+	return pointRecords[T]{w, point}
+}
+
+type pointRecords[T any] struct {
+	w     PointWriter
+	point func(*T) (Point, bool)
+}
+
+func (p pointRecords[T]) Write(records ...T) error {
+	points := make([]Point, 0, len(records))
+	for i := range records {
+		if pt, ok := p.point(&records[i]); ok {
+			points = append(points, pt)
+		}
+	}
+	return p.w.WritePoints(points...)
+}
+
+func (p pointRecords[T]) Flush() error {
+	return p.w.Flush()
+}
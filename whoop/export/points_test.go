@@ -0,0 +1,90 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestWorkoutPoint(t *testing.T) {
+	w := testWorkout()
+	w.UserID = 10
+	w.Score.ZoneDuration.ZoneTwoMilli = 60000
+	p, ok := WorkoutPoint(&w)
+	if !ok {
+		t.Fatal("WorkoutPoint(): expected scored workout to be converted")
+	}
+	if p.Measurement != WorkoutMeasurement || !p.Time.Equal(*w.Start) {
+		t.Errorf("WorkoutPoint(): got measurement %v at %v", p.Measurement, p.Time)
+	}
+	if want := []Tag{{"sport", "Running"}, {"user_id", "10"}}; len(p.Tags) != 2 || p.Tags[0] != want[0] || p.Tags[1] != want[1] {
+		t.Errorf("WorkoutPoint(): got tags %v, want %v", p.Tags, want)
+	}
+	fields := map[string]float64{}
+	for _, f := range p.Fields {
+		fields[f.Key] = f.Value
+	}
+	if fields["strain"] != 12.5 || fields["zone_two_milli"] != 60000 || len(fields) != 10 {
+		t.Errorf("WorkoutPoint(): got fields %v", fields)
+	}
+
+	w.ScoreState = whooptest.Ptr("PENDING_SCORE")
+	if _, ok := WorkoutPoint(&w); ok {
+		t.Error("WorkoutPoint(): expected unscored workout to be skipped")
+	}
+}
+
+func TestRecoveryPoint(t *testing.T) {
+	created := time.Date(2022, 11, 27, 8, 0, 0, 0, time.UTC)
+	r := whoop.Recovery{UserID: 10, CreatedAt: &created, ScoreState: whooptest.Ptr("SCORED")}
+	r.Score.RecoveryScore = 44
+	r.Score.Spo2Percentage = 95.6
+	r.Score.SkinTempCelsius = 33.7
+	testCases := []struct {
+		calibrating bool
+		skinTemp    float64
+		want        []string
+	}{
+		{false, 33.7, []string{"score", "hrv_rmssd_milli", "resting_heart_rate", "spo2_percentage", "skin_temp_celsius"}},
+		{false, 0, []string{"score", "hrv_rmssd_milli", "resting_heart_rate", "spo2_percentage"}},
+		{true, 33.7, []string{"score", "hrv_rmssd_milli", "resting_heart_rate"}},
+	}
+
+	for _, test := range testCases {
+		r.Score.UserCalibrating = test.calibrating
+		r.Score.SkinTempCelsius = test.skinTemp
+		p, ok := RecoveryPoint(&r)
+		if !ok {
+			t.Fatal("RecoveryPoint(): expected scored recovery to be converted")
+		}
+		var got []string
+		for _, f := range p.Fields {
+			got = append(got, f.Key)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("RecoveryPoint(): got fields %v, want %v", got, test.want)
+		}
+	}
+}
+
+func TestPointRecords(t *testing.T) {
+	start := time.Date(2022, 11, 27, 8, 0, 0, 0, time.UTC)
+	scored := whoop.Cycle{UserID: 10, Start: &start, ScoreState: whooptest.Ptr("SCORED")}
+	scored.Score.Strain = 8
+	pending := whoop.Cycle{UserID: 10, Start: &start, ScoreState: whooptest.Ptr("PENDING_SCORE")}
+
+	var buf bytes.Buffer
+	w := PointRecords(NewInfluxWriter(&buf), CyclePoint)
+	if err := w.Write(scored, pending); err != nil {
+		t.Fatalf("PointRecords().Write(): expected nil error, got %v", err)
+	}
+	w.Flush()
+	want := "whoop_cycle,user_id=10 strain=8,kilojoule=0,average_heart_rate=0,max_heart_rate=0 1669536000000000000\n"
+	if got := buf.String(); got != want {
+		t.Errorf("PointRecords().Write(): got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,150 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// RemoteWriteEncoder encodes points as a Prometheus remote-write payload:
+// a snappy-compressed protobuf WriteRequest.
+//
+// Each field of a point becomes a sample of the series named after the
+// measurement and the field, such as whoop_recovery_score, labelled with
+// the tags of the point. Points are buffered until Flush, which writes a
+// single WriteRequest with the samples of each series sorted by time.
+//
+// Each Flush writes a complete payload, and payloads cannot be
+// concatenated: flush once per payload, and send and reset the bytes
+// written to w after each Flush, for example with PostRemoteWrite.
+//
+// Remote-write specification: https://prometheus.io/docs/concepts/remote_write_spec/
+type RemoteWriteEncoder struct {
+	w      io.Writer
+	series map[string]*series
+}
+
+type series struct {
+	labels  []Tag // Sorted by key, starting with __name__.
+	samples []sample
+}
+
+type sample struct {
+	value     float64
+	timestamp int64 // Milliseconds since the epoch.
+}
+
+// NewRemoteWriteEncoder returns a RemoteWriteEncoder writing to w.
+func NewRemoteWriteEncoder(w io.Writer) *RemoteWriteEncoder {
+	return &RemoteWriteEncoder{w: w, series: map[string]*series{}}
+}
+
+// WritePoints buffers the samples of points.
+func (e *RemoteWriteEncoder) WritePoints(points ...Point) error {
+	for _, p := range points {
+		for _, f := range p.Fields {
+			labels := make([]Tag, 0, len(p.Tags)+1)
+			labels = append(labels, Tag{"__name__", p.Measurement + "_" + f.Key})
+			for _, t := range p.Tags {
+				if t.Value != "" {
+					labels = append(labels, t)
+				}
+			}
+			sort.SliceStable(labels[1:], func(i, j int) bool { return labels[i+1].Key < labels[j+1].Key })
+
+			var key strings.Builder
+			for _, l := range labels {
+				fmt.Fprintf(&key, "%s\xff%s\xff", l.Key, l.Value)
+			}
+			s, ok := e.series[key.String()]
+			if !ok {
+				s = &series{labels: labels}
+				e.series[key.String()] = s
+			}
+			s.samples = append(s.samples, sample{f.Value, p.Time.UnixMilli()})
+		}
+	}
+	return nil
+}
+
+// Flush writes the buffered samples as a WriteRequest payload and resets
+// the encoder, so that the next Flush writes a payload of the samples
+// buffered since. Nothing is written if no samples are buffered.
+func (e *RemoteWriteEncoder) Flush() error {
+	if len(e.series) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(e.series))
+	for k := range e.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// WriteRequest{repeated TimeSeries timeseries = 1}
+	// TimeSeries{repeated Label labels = 1; repeated Sample samples = 2}
+	// Label{string name = 1; string value = 2}
+	// Sample{double value = 1; int64 timestamp = 2}
+	var req []byte
+	for _, k := range keys {
+		s := e.series[k]
+		sort.SliceStable(s.samples, func(i, j int) bool { return s.samples[i].timestamp < s.samples[j].timestamp })
+		var ts []byte
+		for _, l := range s.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l.Key)
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l.Value)
+			ts = protowire.AppendTag(ts, 1, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, label)
+		}
+		for _, smp := range s.samples {
+			var b []byte
+			b = protowire.AppendTag(b, 1, protowire.Fixed64Type)
+			b = protowire.AppendFixed64(b, math.Float64bits(smp.value))
+			b = protowire.AppendTag(b, 2, protowire.VarintType)
+			b = protowire.AppendVarint(b, uint64(smp.timestamp))
+			ts = protowire.AppendTag(ts, 2, protowire.BytesType)
+			ts = protowire.AppendBytes(ts, b)
+		}
+		req = protowire.AppendTag(req, 1, protowire.BytesType)
+		req = protowire.AppendBytes(req, ts)
+	}
+	e.series = map[string]*series{}
+	_, err := e.w.Write(snappy.Encode(nil, req))
+	return err
+}
+
+// PostRemoteWrite sends a payload written by a RemoteWriteEncoder to the
+// remote-write endpoint at url. If client is nil, http.DefaultClient is
+// used.
+func PostRemoteWrite(ctx context.Context, client *http.Client, url string, payload []byte) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("remote write: %v: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// decodedSeries is a TimeSeries decoded from a WriteRequest.
+type decodedSeries struct {
+	Labels  map[string]string
+	Samples []sample
+}
+
+func decodeWriteRequest(t *testing.T, payload []byte) []decodedSeries {
+	t.Helper()
+	b, err := snappy.Decode(nil, payload)
+	if err != nil {
+		t.Fatalf("snappy.Decode(): %v", err)
+	}
+	fields := func(b []byte, fn func(num protowire.Number, typ protowire.Type, b []byte) int) {
+		for len(b) > 0 {
+			num, typ, n := protowire.ConsumeTag(b)
+			if n < 0 {
+				t.Fatalf("protowire.ConsumeTag(): %v", protowire.ParseError(n))
+			}
+			b = b[n:]
+			n = fn(num, typ, b)
+			if n < 0 {
+				t.Fatalf("protowire: %v", protowire.ParseError(n))
+			}
+			b = b[n:]
+		}
+	}
+	var out []decodedSeries
+	fields(b, func(_ protowire.Number, _ protowire.Type, b []byte) int {
+		ts, n := protowire.ConsumeBytes(b)
+		s := decodedSeries{Labels: map[string]string{}}
+		fields(ts, func(num protowire.Number, _ protowire.Type, b []byte) int {
+			msg, n := protowire.ConsumeBytes(b)
+			if num == 1 {
+				var name string
+				fields(msg, func(num protowire.Number, _ protowire.Type, b []byte) int {
+					v, n := protowire.ConsumeString(b)
+					if num == 1 {
+						name = v
+					} else {
+						s.Labels[name] = v
+					}
+					return n
+				})
+			} else {
+				var smp sample
+				fields(msg, func(num protowire.Number, typ protowire.Type, b []byte) int {
+					if num == 1 {
+						v, n := protowire.ConsumeFixed64(b)
+						smp.value = math.Float64frombits(v)
+						return n
+					}
+					v, n := protowire.ConsumeVarint(b)
+					smp.timestamp = int64(v)
+					return n
+				})
+				s.Samples = append(s.Samples, smp)
+			}
+			return n
+		})
+		out = append(out, s)
+		return n
+	})
+	return out
+}
+
+func TestRemoteWriteEncoder(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewRemoteWriteEncoder(&buf)
+	t1 := time.UnixMilli(2000)
+	t0 := time.UnixMilli(1000)
+	e.WritePoints(
+		Point{Measurement: "whoop_recovery", Tags: []Tag{{"user_id", "10"}}, Fields: []Field{{"score", 45}, {"hrv_rmssd_milli", 61.5}}, Time: t1},
+		Point{Measurement: "whoop_recovery", Tags: []Tag{{"user_id", "10"}}, Fields: []Field{{"score", 80}}, Time: t0},
+	)
+	if err := e.Flush(); err != nil {
+		t.Fatalf("RemoteWriteEncoder.Flush(): expected nil error, got %v", err)
+	}
+
+	got := decodeWriteRequest(t, buf.Bytes())
+	want := []decodedSeries{
+		{Labels: map[string]string{"__name__": "whoop_recovery_hrv_rmssd_milli", "user_id": "10"}, Samples: []sample{{61.5, 2000}}},
+		{Labels: map[string]string{"__name__": "whoop_recovery_score", "user_id": "10"}, Samples: []sample{{80, 1000}, {45, 2000}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoteWriteEncoder.Flush(): got %+v, want %+v", got, want)
+	}
+
+	buf.Reset()
+	if err := e.Flush(); err != nil || buf.Len() != 0 {
+		t.Errorf("RemoteWriteEncoder.Flush(): expected nothing to be written after reset, got %v bytes, error %v", buf.Len(), err)
+	}
+}
+
+func TestRemoteWriteEncoder_payloads(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewRemoteWriteEncoder(&buf)
+	tags := []Tag{{"user_id", "10"}}
+	var got [][]decodedSeries
+	for i, score := range []float64{45, 80} {
+		e.WritePoints(Point{Measurement: "whoop_recovery", Tags: tags, Fields: []Field{{"score", score}}, Time: time.UnixMilli(int64(i))})
+		if err := e.Flush(); err != nil {
+			t.Fatalf("RemoteWriteEncoder.Flush(): expected nil error, got %v", err)
+		}
+		got = append(got, decodeWriteRequest(t, buf.Bytes()))
+		buf.Reset()
+	}
+
+	labels := map[string]string{"__name__": "whoop_recovery_score", "user_id": "10"}
+	want := [][]decodedSeries{
+		{{Labels: labels, Samples: []sample{{45, 0}}}},
+		{{Labels: labels, Samples: []sample{{80, 1}}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RemoteWriteEncoder.Flush(): got payloads %+v, want %+v", got, want)
+	}
+}
+
+func TestPostRemoteWrite(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Encoding"); got != "snappy" {
+			t.Errorf("PostRemoteWrite(): expected snappy encoding, got %v", got)
+		}
+		if body, _ := io.ReadAll(r.Body); string(body) != "payload" {
+			t.Errorf("PostRemoteWrite(): got body %q", body)
+		}
+		if r.URL.Path == "/fail" {
+			http.Error(w, "out of order sample", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	if err := PostRemoteWrite(context.Background(), nil, srv.URL+"/write", []byte("payload")); err != nil {
+		t.Errorf("PostRemoteWrite(): expected nil error, got %v", err)
+	}
+	if err := PostRemoteWrite(context.Background(), nil, srv.URL+"/fail", []byte("payload")); err == nil {
+		t.Error("PostRemoteWrite(): expected error for status 400, got nil")
+	}
+}
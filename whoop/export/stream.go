@@ -0,0 +1,88 @@
+package export
+
+import (
+	"context"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// StreamCycles pages through the cycles matching params and writes them
+// to w page by page, so large histories are never loaded into memory.
+// params.NextToken is used as the first page token, if set. The writer is
+// flushed once every page has been written.
+func StreamCycles(ctx context.Context, client *whoop.Client, params *whoop.RequestParams, w RecordWriter[whoop.Cycle]) error {
+	return stream(ctx, params, w, func(ctx context.Context, p *whoop.RequestParams) ([]whoop.Cycle, *string, error) {
+		resp, err := client.Cycle.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	})
+}
+
+// StreamRecoveries pages through the recoveries matching params and writes
+// them to w page by page. See StreamCycles.
+func StreamRecoveries(ctx context.Context, client *whoop.Client, params *whoop.RequestParams, w RecordWriter[whoop.Recovery]) error {
+	return stream(ctx, params, w, func(ctx context.Context, p *whoop.RequestParams) ([]whoop.Recovery, *string, error) {
+		resp, err := client.Recovery.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	})
+}
+
+// StreamSleeps pages through the sleeps matching params and writes them
+// to w page by page. See StreamCycles.
+func StreamSleeps(ctx context.Context, client *whoop.Client, params *whoop.RequestParams, w RecordWriter[whoop.Sleep]) error {
+	return stream(ctx, params, w, func(ctx context.Context, p *whoop.RequestParams) ([]whoop.Sleep, *string, error) {
+		resp, err := client.Sleep.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	})
+}
+
+// StreamWorkouts pages through the workouts matching params and writes
+// them to w page by page. See StreamCycles.
+func StreamWorkouts(ctx context.Context, client *whoop.Client, params *whoop.RequestParams, w RecordWriter[whoop.Workout]) error {
+	return stream(ctx, params, w, func(ctx context.Context, p *whoop.RequestParams) ([]whoop.Workout, *string, error) {
+		resp, err := client.Workout.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	})
+}
+
+// pageFunc fetches a page of records.
+type pageFunc[T any] func(ctx context.Context, params *whoop.RequestParams) ([]T, *string, error)
+
+// RecordWriter is a writer of records of type T, such as a CSVWriter
+// or a ParquetWriter.
+type RecordWriter[T any] interface {
+	Write(records ...T) error
+	Flush() error
+}
+
+func stream[T any](ctx context.Context, params *whoop.RequestParams, w RecordWriter[T], page pageFunc[T]) error {
+	var p whoop.RequestParams
+	if params != nil {
+		p = *params
+	}
+	for {
+		records, next, err := page(ctx, &p)
+		if err != nil {
+			return err
+		}
+		if err := w.Write(records...); err != nil {
+			return err
+		}
+		if next == nil || *next == "" {
+			break
+		}
+		p.NextToken = *next
+	}
+	return w.Flush()
+}
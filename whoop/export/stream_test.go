@@ -0,0 +1,38 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestStreamWorkouts(t *testing.T) {
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "2" {
+			t.Errorf("StreamWorkouts(): expected limit 2, got %v", r.URL.Query().Get("limit"))
+		}
+		switch r.URL.Query().Get("nextToken") {
+		case "":
+			fmt.Fprint(w, `{"records": [{"id": 1, "sport_id": 0}, {"id": 2, "sport_id": 1}], "next_token": "p2"}`)
+		case "p2":
+			fmt.Fprint(w, `{"records": [{"id": 3, "sport_id": 44}], "next_token": null}`)
+		default:
+			t.Errorf("StreamWorkouts(): unexpected token %v", r.URL.Query().Get("nextToken"))
+		}
+	}))
+
+	var buf bytes.Buffer
+	w, _ := NewWorkoutWriter(&buf, CSVOptions{Columns: []string{"id", "sport_name"}})
+	if err := StreamWorkouts(context.Background(), client, &whoop.RequestParams{Limit: 2}, w); err != nil {
+		t.Fatalf("StreamWorkouts(): expected nil error, got %v", err)
+	}
+	want := "id,sport_name\n1,Running\n2,Cycling\n3,Yoga\n"
+	if got := buf.String(); got != want {
+		t.Errorf("StreamWorkouts(): got\n%v\nwant\n%v", got, want)
+	}
+}
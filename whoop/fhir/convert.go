@@ -0,0 +1,228 @@
+package fhir
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// metric describes how a WHOOP metric is coded as an Observation.
+type metric struct {
+	id       string // Suffix of the Observation ID.
+	system   string
+	code     string
+	display  string
+	category string
+	unit     string // UCUM unit code.
+}
+
+var (
+	restingHeartRate = metric{"resting-heart-rate", LOINC, "40443-4", "Heart rate --resting", "vital-signs", "/min"}
+	hrvRmssd         = metric{"hrv-rmssd", WhoopCodeSystem, "hrv-rmssd", "Heart rate variability RMSSD", "vital-signs", "ms"}
+	spo2             = metric{"spo2", LOINC, "59408-5", "Oxygen saturation in Arterial blood by Pulse oximetry", "vital-signs", "%"}
+	skinTemp         = metric{"skin-temperature", LOINC, "39106-0", "Temperature of Skin", "vital-signs", "Cel"}
+	recoveryScore    = metric{"recovery-score", WhoopCodeSystem, "recovery-score", "WHOOP recovery score", "activity", "%"}
+	sleepDuration    = metric{"sleep-duration", LOINC, "93832-4", "Sleep duration", "activity", "h"}
+	timeInBed        = metric{"time-in-bed", WhoopCodeSystem, "time-in-bed", "Time in bed", "activity", "h"}
+	remSleep         = metric{"rem-sleep", WhoopCodeSystem, "rem-sleep", "REM sleep duration", "activity", "h"}
+	slowWaveSleep    = metric{"slow-wave-sleep", WhoopCodeSystem, "slow-wave-sleep", "Slow wave sleep duration", "activity", "h"}
+	respiratoryRate  = metric{"respiratory-rate", LOINC, "9279-1", "Respiratory rate", "vital-signs", "/min"}
+	strain           = metric{"strain", WhoopCodeSystem, "strain", "WHOOP day strain", "activity", "{score}"}
+	energy           = metric{"energy-expenditure", LOINC, "41979-6", "Calories burned in 24 hour Calculated", "activity", "kcal"}
+	bodyHeight       = metric{"body-height", LOINC, "8302-2", "Body height", "vital-signs", "m"}
+	bodyWeight       = metric{"body-weight", LOINC, "29463-7", "Body weight", "vital-signs", "kg"}
+)
+
+const kilojoulesPerKilocalorie = 4.184
+
+// Converter converts the WHOOP records of a single member into FHIR resources.
+type Converter struct {
+	// Profile is the member the records belong to. Required.
+	Profile *whoop.UserProfile
+
+	// MeasuredAt is the effective time of body measurement observations,
+	// since the API doesn't report when they were taken. If zero, the
+	// observations have no effective time.
+	MeasuredAt time.Time
+}
+
+// Patient returns the Patient resource of the member.
+func (c Converter) Patient() *Patient {
+	p := &Patient{
+		ResourceType: "Patient",
+		ID:           c.patientID(),
+		Identifier:   []Identifier{{System: WhoopUserSystem, Value: fmt.Sprint(c.Profile.ID)}},
+	}
+	var name HumanName
+	if c.Profile.LastName != nil {
+		name.Family = *c.Profile.LastName
+	}
+	if c.Profile.FirstName != nil {
+		name.Given = []string{*c.Profile.FirstName}
+	}
+	if name.Family != "" || len(name.Given) > 0 {
+		p.Name = []HumanName{name}
+	}
+	if c.Profile.Email != nil {
+		p.Telecom = []Telecom{{System: "email", Value: *c.Profile.Email}}
+	}
+	return p
+}
+
+func (c Converter) patientID() string {
+	return fmt.Sprintf("whoop-%v", c.Profile.ID)
+}
+
+// observation returns the Observation of m with the given value.
+func (c Converter) observation(resource string, id int, m metric, value float64) *Observation {
+	return &Observation{
+		ResourceType: "Observation",
+		ID:           fmt.Sprintf("whoop-%v-%v-%v", resource, id, m.id),
+		Status:       "final",
+		Category:     []CodeableConcept{{Coding: []Coding{{System: CategorySystem, Code: m.category}}}},
+		Code:         CodeableConcept{Coding: []Coding{{System: m.system, Code: m.code, Display: m.display}}, Text: m.display},
+		Subject:      &Reference{Reference: fullURL("Patient", c.patientID())},
+		ValueQuantity: &Quantity{
+			Value:  value,
+			Unit:   m.unit,
+			System: UCUM,
+			Code:   m.unit,
+		},
+	}
+}
+
+// Recovery returns the resting heart rate, HRV, SpO2, skin temperature and
+// recovery score observations of r, effective at the time r was created.
+// It returns nil if r is not scored. SpO2 and skin temperature are omitted
+// while the member is calibrating, as the API doesn't report them then.
+func (c Converter) Recovery(r *whoop.Recovery) []*Observation {
+	if !r.Scored() {
+		return nil
+	}
+	s := r.Score
+	obs := []*Observation{
+		c.observation("recovery", r.CycleID, recoveryScore, s.RecoveryScore),
+		c.observation("recovery", r.CycleID, restingHeartRate, s.RestingHeartRate),
+		c.observation("recovery", r.CycleID, hrvRmssd, s.HrvRmssdMilli),
+	}
+	if s.Spo2Percentage != 0 {
+		obs = append(obs, c.observation("recovery", r.CycleID, spo2, s.Spo2Percentage))
+	}
+	if s.SkinTempCelsius != 0 {
+		obs = append(obs, c.observation("recovery", r.CycleID, skinTemp, s.SkinTempCelsius))
+	}
+	for _, o := range obs {
+		o.EffectiveDateTime = r.CreatedAt
+	}
+	return obs
+}
+
+// Sleep returns the sleep duration, time in bed, REM and slow wave sleep
+// and respiratory rate observations of s, effective over the sleep period.
+// It returns nil if s is not scored.
+func (c Converter) Sleep(s *whoop.Sleep) []*Observation {
+	if !s.Scored() {
+		return nil
+	}
+	st := s.Score.StageSummary
+	obs := []*Observation{
+		c.observation("sleep", s.ID, sleepDuration, s.TimeAsleep().Hours()),
+		c.observation("sleep", s.ID, timeInBed, hours(st.TotalInBedTimeMilli)),
+		c.observation("sleep", s.ID, remSleep, hours(st.TotalRemSleepTimeMilli)),
+		c.observation("sleep", s.ID, slowWaveSleep, hours(st.TotalSlowWaveSleepTimeMilli)),
+	}
+	if s.Score.RespiratoryRate != 0 {
+		obs = append(obs, c.observation("sleep", s.ID, respiratoryRate, s.Score.RespiratoryRate))
+	}
+	for _, o := range obs {
+		o.EffectivePeriod = &Period{Start: s.Start, End: s.End}
+	}
+	return obs
+}
+
+// Cycle returns the strain and energy expenditure observations of cy,
+// effective over the cycle period. Energy is converted to kilocalories,
+// the unit of its LOINC code. It returns nil if cy is not scored.
+func (c Converter) Cycle(cy *whoop.Cycle) []*Observation {
+	if !cy.Scored() {
+		return nil
+	}
+	obs := []*Observation{
+		c.observation("cycle", cy.ID, strain, cy.Score.Strain),
+		c.observation("cycle", cy.ID, energy, cy.Score.Kilojoule/kilojoulesPerKilocalorie),
+	}
+	for _, o := range obs {
+		o.EffectivePeriod = &Period{Start: cy.Start, End: cy.End}
+		if cy.End == nil {
+			// The cycle is still in progress, so its scores may change.
+			o.Status = "preliminary"
+		}
+	}
+	return obs
+}
+
+// BodyMeasurement returns the body height and weight observations of m,
+// effective at c.MeasuredAt.
+func (c Converter) BodyMeasurement(m *whoop.BodyMeasurement) []*Observation {
+	var obs []*Observation
+	if m.HeightMeter != 0 {
+		obs = append(obs, c.observation("body", c.Profile.ID, bodyHeight, m.HeightMeter))
+	}
+	if m.WeightKilogram != 0 {
+		obs = append(obs, c.observation("body", c.Profile.ID, bodyWeight, m.WeightKilogram))
+	}
+	if !c.MeasuredAt.IsZero() {
+		for _, o := range obs {
+			o.EffectiveDateTime = &c.MeasuredAt
+		}
+	}
+	return obs
+}
+
+// Bundle returns a collection Bundle holding the Patient of the member and
+// the observations of records, which must be *whoop.Recovery, *whoop.Sleep,
+// *whoop.Cycle or *whoop.BodyMeasurement values.
+func (c Converter) Bundle(records ...any) (*Bundle, error) {
+	if c.Profile == nil {
+		return nil, fmt.Errorf("fhir: Converter requires a Profile")
+	}
+	b := &Bundle{ResourceType: "Bundle", Type: "collection"}
+	p := c.Patient()
+	b.Entry = append(b.Entry, BundleEntry{FullURL: fullURL("Patient", p.ID), Resource: p})
+	for _, r := range records {
+		var obs []*Observation
+		switch r := r.(type) {
+		case *whoop.Recovery:
+			obs = c.Recovery(r)
+		case *whoop.Sleep:
+			obs = c.Sleep(r)
+		case *whoop.Cycle:
+			obs = c.Cycle(r)
+		case *whoop.BodyMeasurement:
+			obs = c.BodyMeasurement(r)
+		default:
+			return nil, fmt.Errorf("fhir: unsupported record type %T", r)
+		}
+		for _, o := range obs {
+			b.Entry = append(b.Entry, BundleEntry{FullURL: fullURL("Observation", o.ID), Resource: o})
+		}
+	}
+	return b, nil
+}
+
+// fullURL returns the urn:uuid full URL of a resource, used to reference it
+// within a Bundle. The UUID is derived from the resource type and ID, so
+// converting the same record twice yields the same URL.
+func fullURL(resourceType, id string) string {
+	h := sha1.Sum([]byte(resourceType + "/" + id))
+	h[6] = h[6]&0x0f | 0x50 // Version 5, name-based with SHA-1.
+	h[8] = h[8]&0x3f | 0x80 // RFC 4122 variant.
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", h[0:4], h[4:6], h[6:8], h[8:10], h[10:16])
+}
+
+// hours converts milliseconds to hours.
+func hours(ms int) float64 {
+	return float64(ms) / float64(time.Hour/time.Millisecond)
+}
@@ -0,0 +1,116 @@
+package fhir
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func testConverter() Converter {
+	return Converter{Profile: &whoop.UserProfile{ID: 10, FirstName: whooptest.Ptr("Ana"), LastName: whooptest.Ptr("Lee"), Email: whooptest.Ptr("ana@example.com")}}
+}
+
+func TestConverter_Recovery(t *testing.T) {
+	created := time.Date(2022, 11, 27, 8, 0, 0, 0, time.UTC)
+	r := &whoop.Recovery{CycleID: 1, UserID: 10, CreatedAt: &created, ScoreState: whooptest.Ptr("SCORED")}
+	r.Score.RecoveryScore = 44
+	r.Score.RestingHeartRate = 64
+	r.Score.HrvRmssdMilli = 31.8
+	r.Score.Spo2Percentage = 95.6
+
+	obs := testConverter().Recovery(r)
+	if len(obs) != 4 {
+		t.Fatalf("Converter.Recovery(): expected 4 observations, got %v", len(obs))
+	}
+	rhr := obs[1]
+	if rhr.Code.Coding[0].System != LOINC || rhr.Code.Coding[0].Code != "40443-4" {
+		t.Errorf("Converter.Recovery(): expected LOINC 40443-4, got %+v", rhr.Code)
+	}
+	if rhr.ValueQuantity.Value != 64 || rhr.ValueQuantity.Code != "/min" || rhr.ValueQuantity.System != UCUM {
+		t.Errorf("Converter.Recovery(): got quantity %+v", rhr.ValueQuantity)
+	}
+	if !rhr.EffectiveDateTime.Equal(created) || rhr.Status != "final" || rhr.ID != "whoop-recovery-1-resting-heart-rate" {
+		t.Errorf("Converter.Recovery(): got %+v", rhr)
+	}
+	if got := obs[3].Code.Coding[0].Code; got != "59408-5" {
+		t.Errorf("Converter.Recovery(): expected SpO2 LOINC 59408-5, got %v", got)
+	}
+
+	r.ScoreState = whooptest.Ptr("PENDING_SCORE")
+	if obs := testConverter().Recovery(r); obs != nil {
+		t.Errorf("Converter.Recovery(): expected no observations for unscored recovery, got %v", obs)
+	}
+}
+
+func TestConverter_Bundle(t *testing.T) {
+	start := time.Date(2022, 11, 26, 23, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+	s := &whoop.Sleep{ID: 5, Start: &start, End: &end, ScoreState: whooptest.Ptr("SCORED")}
+	s.Score.StageSummary.TotalLightSleepTimeMilli = 4 * 3600000
+	s.Score.StageSummary.TotalRemSleepTimeMilli = 2 * 3600000
+	s.Score.StageSummary.TotalSlowWaveSleepTimeMilli = 3600000
+	cy := &whoop.Cycle{ID: 2, Start: &start, ScoreState: whooptest.Ptr("SCORED")}
+	cy.Score.Strain = 12.1
+	cy.Score.Kilojoule = 8368
+
+	c := testConverter()
+	c.MeasuredAt = end
+	b, err := c.Bundle(s, cy, &whoop.BodyMeasurement{HeightMeter: 1.7, WeightKilogram: 60})
+	if err != nil {
+		t.Fatalf("Converter.Bundle(): expected nil error, got %v", err)
+	}
+	// Patient, 4 sleep, 2 cycle and 2 body observations.
+	if len(b.Entry) != 9 {
+		t.Fatalf("Converter.Bundle(): expected 9 entries, got %v", len(b.Entry))
+	}
+	p := b.Entry[0].Resource.(*Patient)
+	if p.Name[0].Family != "Lee" || p.Identifier[0].Value != "10" {
+		t.Errorf("Converter.Bundle(): got patient %+v", p)
+	}
+	sleep := b.Entry[1].Resource.(*Observation)
+	if sleep.Code.Coding[0].Code != "93832-4" || sleep.ValueQuantity.Value != 7 || !sleep.EffectivePeriod.End.Equal(end) {
+		t.Errorf("Converter.Bundle(): got sleep duration %+v", sleep)
+	}
+	if sleep.Subject.Reference != b.Entry[0].FullURL || !strings.HasPrefix(b.Entry[0].FullURL, "urn:uuid:") {
+		t.Errorf("Converter.Bundle(): expected subject to reference patient %v, got %v", b.Entry[0].FullURL, sleep.Subject.Reference)
+	}
+	if strain := b.Entry[5].Resource.(*Observation); strain.Status != "preliminary" {
+		t.Errorf("Converter.Bundle(): expected preliminary strain for unfinished cycle, got %v", strain.Status)
+	}
+	energy := b.Entry[6].Resource.(*Observation)
+	if energy.Code.Coding[0].Code != "41979-6" || energy.ValueQuantity.Value != 2000 || energy.ValueQuantity.Code != "kcal" {
+		t.Errorf("Converter.Bundle(): got energy %+v", energy.ValueQuantity)
+	}
+	weight := b.Entry[8].Resource.(*Observation)
+	if weight.Code.Coding[0].Code != "29463-7" || !weight.EffectiveDateTime.Equal(end) {
+		t.Errorf("Converter.Bundle(): got weight %+v", weight)
+	}
+
+	data, _ := json.Marshal(b)
+	for _, want := range []string{`"resourceType":"Bundle"`, `"type":"collection"`, `"resourceType":"Observation"`, `"effectivePeriod":{`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Converter.Bundle(): expected JSON to contain %v", want)
+		}
+	}
+
+	if _, err := c.Bundle(&whoop.Workout{}); err == nil {
+		t.Error("Converter.Bundle(): expected error for unsupported record, got nil")
+	}
+	if _, err := (Converter{}).Bundle(); err == nil {
+		t.Error("Converter.Bundle(): expected error for missing profile, got nil")
+	}
+}
+
+func TestFullURL(t *testing.T) {
+	a, b := fullURL("Patient", "whoop-1"), fullURL("Patient", "whoop-1")
+	if a != b || len(a) != len("urn:uuid:")+36 || a[len("urn:uuid:")+14] != '5' {
+		t.Errorf("fullURL(): got %v and %v, want equal version 5 UUIDs", a, b)
+	}
+	if fullURL("Patient", "whoop-2") == a {
+		t.Error("fullURL(): expected different URLs for different resources")
+	}
+}
@@ -0,0 +1,108 @@
+// Package fhir converts WHOOP records into HL7 FHIR R4 resources.
+//
+// Recovery, sleep, cycle and body measurement metrics are mapped to
+// Observation resources, coded with LOINC where a matching code exists and
+// with the WHOOP code system otherwise, referencing a Patient built from
+// the member's UserProfile. Resources are collected in a Bundle which can
+// be marshalled to FHIR JSON.
+//
+// FHIR R4 specification: https://hl7.org/fhir/R4/
+package fhir
+
+import "time"
+
+// Code systems used by the converted resources.
+const (
+	LOINC           = "http://loinc.org"
+	UCUM            = "http://unitsofmeasure.org"
+	CategorySystem  = "http://terminology.hl7.org/CodeSystem/observation-category"
+	WhoopCodeSystem = "urn:whoop:metric"  // Codes of WHOOP metrics without a LOINC equivalent.
+	WhoopUserSystem = "urn:whoop:user-id" // Identifier system of WHOOP user IDs.
+)
+
+// Bundle is a FHIR Bundle resource.
+type Bundle struct {
+	ResourceType string        `json:"resourceType"`
+	Type         string        `json:"type"`
+	Timestamp    *time.Time    `json:"timestamp,omitempty"`
+	Entry        []BundleEntry `json:"entry,omitempty"`
+}
+
+// BundleEntry is an entry of a Bundle.
+type BundleEntry struct {
+	FullURL  string `json:"fullUrl,omitempty"`
+	Resource any    `json:"resource"` // A *Patient or an *Observation.
+}
+
+// Patient is a FHIR Patient resource.
+type Patient struct {
+	ResourceType string       `json:"resourceType"`
+	ID           string       `json:"id"`
+	Identifier   []Identifier `json:"identifier,omitempty"`
+	Name         []HumanName  `json:"name,omitempty"`
+	Telecom      []Telecom    `json:"telecom,omitempty"`
+}
+
+// Observation is a FHIR Observation resource.
+type Observation struct {
+	ResourceType      string            `json:"resourceType"`
+	ID                string            `json:"id"`
+	Status            string            `json:"status"`
+	Category          []CodeableConcept `json:"category,omitempty"`
+	Code              CodeableConcept   `json:"code"`
+	Subject           *Reference        `json:"subject,omitempty"`
+	EffectiveDateTime *time.Time        `json:"effectiveDateTime,omitempty"`
+	EffectivePeriod   *Period           `json:"effectivePeriod,omitempty"`
+	ValueQuantity     *Quantity         `json:"valueQuantity,omitempty"`
+}
+
+// Identifier is a FHIR Identifier.
+type Identifier struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+// HumanName is a FHIR HumanName.
+type HumanName struct {
+	Family string   `json:"family,omitempty"`
+	Given  []string `json:"given,omitempty"`
+}
+
+// Telecom is a FHIR ContactPoint.
+type Telecom struct {
+	System string `json:"system"`
+	Value  string `json:"value"`
+}
+
+// CodeableConcept is a FHIR CodeableConcept.
+type CodeableConcept struct {
+	Coding []Coding `json:"coding"`
+	Text   string   `json:"text,omitempty"`
+}
+
+// Coding is a FHIR Coding.
+type Coding struct {
+	System  string `json:"system"`
+	Code    string `json:"code"`
+	Display string `json:"display,omitempty"`
+}
+
+// Reference is a FHIR Reference to another resource.
+type Reference struct {
+	Reference string `json:"reference"`
+	Display   string `json:"display,omitempty"`
+}
+
+// Period is a FHIR Period.
+type Period struct {
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// Quantity is a FHIR Quantity with a UCUM unit.
+type Quantity struct {
+	Value  float64 `json:"value"`
+	Unit   string  `json:"unit"`
+	System string  `json:"system"`
+	Code   string  `json:"code"`
+}
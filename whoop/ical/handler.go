@@ -0,0 +1,122 @@
+package ical
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// ErrUnknownUser is returned by a Handler's Client function for users who
+// have no feed. The handler responds with 404 Not Found.
+var ErrUnknownUser = errors.New("ical: unknown user")
+
+// defaultWindow is the default period covered by a feed.
+const defaultWindow = 30 * 24 * time.Hour
+
+// now returns the current time. It is replaced in tests.
+var now = time.Now
+
+// Handler serves a subscribable calendar feed of a user's sleeps and
+// workouts. The user is the last element of the request path, with an
+// optional .ics extension, so a handler mounted at /calendars/ serves the
+// feed of user 42 at /calendars/42.ics.
+type Handler struct {
+	// Client returns the API client of user. It returns ErrUnknownUser
+	// if the user has no feed.
+	Client func(ctx context.Context, user string) (*whoop.Client, error)
+
+	// Window is the period before the request covered by the feed.
+	// Defaults to 30 days.
+	Window time.Duration
+
+	// Logger logs feeds which could not be written to the client, such as
+	// when the connection is closed. Nil disables logging.
+	Logger *slog.Logger
+}
+
+// ServeHTTP serves the feed of the requested user.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	user := strings.TrimSuffix(path.Base(r.URL.Path), ".ics")
+	if user == "" || user == "/" || user == "." {
+		http.NotFound(w, r)
+		return
+	}
+
+	ctx := r.Context()
+	client, err := h.Client(ctx, user)
+	if errors.Is(err, ErrUnknownUser) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	window := h.Window
+	if window == 0 {
+		window = defaultWindow
+	}
+	params := whoop.RequestParams{Start: now().Add(-window)}
+	cal := Calendar{Name: "WHOOP"}
+	cal.Sleeps, err = listAll(ctx, params, func(ctx context.Context, p *whoop.RequestParams) ([]whoop.Sleep, *string, error) {
+		resp, err := client.Sleep.ListAll(ctx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Records, resp.NextToken, nil
+	})
+	if err == nil {
+		cal.Workouts, err = listAll(ctx, params, func(ctx context.Context, p *whoop.RequestParams) ([]whoop.Workout, *string, error) {
+			resp, err := client.Workout.ListAll(ctx, p)
+			if err != nil {
+				return nil, nil, err
+			}
+			return resp.Records, resp.NextToken, nil
+		})
+	}
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": user + ".ics"}))
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := Write(w, cal); err != nil && h.Logger != nil {
+		h.Logger.LogAttrs(ctx, slog.LevelWarn, "ical: write feed",
+			slog.String("user", user),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// listAll fetches every page of records matching params.
+func listAll[T any](ctx context.Context, params whoop.RequestParams, page func(context.Context, *whoop.RequestParams) ([]T, *string, error)) ([]T, error) {
+	var all []T
+	for {
+		records, next, err := page(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, records...)
+		if next == nil || *next == "" {
+			return all, nil
+		}
+		params.NextToken = *next
+	}
+}
@@ -0,0 +1,101 @@
+package ical
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestHandler(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC) }
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.Query().Get("start"), "2022-11-01T00:00:00Z"; got != want {
+			t.Errorf("Handler: expected start %v, got %v", want, got)
+		}
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/activity/sleep") && r.URL.Query().Get("nextToken") == "":
+			fmt.Fprint(w, `{"records": [{"id": 1, "start": "2022-11-27T06:00:00Z", "end": "2022-11-27T14:00:00Z"}], "next_token": "page2"}`)
+		case strings.HasSuffix(r.URL.Path, "/activity/sleep"):
+			fmt.Fprint(w, `{"records": [{"id": 2, "start": "2022-11-28T06:00:00Z", "end": "2022-11-28T14:00:00Z"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/activity/workout"):
+			fmt.Fprint(w, `{"records": [{"id": 3, "sport_id": 0, "start": "2022-11-28T16:00:00Z", "end": "2022-11-28T17:00:00Z"}]}`)
+		default:
+			t.Errorf("Handler: unexpected request to %v", r.URL.Path)
+		}
+	}))
+
+	h := &Handler{Client: func(ctx context.Context, user string) (*whoop.Client, error) {
+		if user != "42" {
+			return nil, ErrUnknownUser
+		}
+		return client, nil
+	}}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendars/42.ics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Handler: expected status 200, got %v", rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/calendar; charset=utf-8" {
+		t.Errorf("Handler: expected calendar content type, got %v", got)
+	}
+	body := rec.Body.String()
+	for _, uid := range []string{"UID:sleep-1@whoop", "UID:sleep-2@whoop", "UID:workout-3@whoop", "SUMMARY:Running"} {
+		if !strings.Contains(body, uid) {
+			t.Errorf("Handler: expected feed to contain %v, got\n%v", uid, body)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendars/7.ics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Handler: expected status 404 for unknown user, got %v", rec.Code)
+	}
+}
+
+func TestHandler_apiError(t *testing.T) {
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+
+	h := &Handler{Client: func(ctx context.Context, user string) (*whoop.Client, error) { return client, nil }}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/calendars/42.ics", nil))
+	if rec.Code != http.StatusBadGateway {
+		t.Errorf("Handler: expected status 502, got %v", rec.Code)
+	}
+}
+
+type failingWriter struct{ *httptest.ResponseRecorder }
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("connection closed")
+}
+
+func TestHandler_writeError(t *testing.T) {
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"records": []}`)
+	}))
+
+	var buf bytes.Buffer
+	h := &Handler{
+		Client: func(ctx context.Context, user string) (*whoop.Client, error) { return client, nil },
+		Logger: slog.New(slog.NewTextHandler(&buf, nil)),
+	}
+	h.ServeHTTP(failingWriter{httptest.NewRecorder()}, httptest.NewRequest(http.MethodGet, "/calendars/42.ics", nil))
+	if got := buf.String(); !strings.Contains(got, "level=WARN") || !strings.Contains(got, "user=42") || !strings.Contains(got, `error="connection closed"`) {
+		t.Errorf("Handler: expected write error to be logged, got %q", got)
+	}
+}
@@ -0,0 +1,234 @@
+// Package ical renders WHOOP sleeps and workouts as iCalendar (RFC 5545)
+// events, and serves them as subscribable per-user calendar feeds.
+//
+// Events are written in the time zone the record was recorded in, taken
+// from its TimezoneOffset, with a VTIMEZONE definition for each offset
+// used by the calendar.
+//
+// iCalendar specification: https://www.rfc-editor.org/rfc/rfc5545
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+const (
+	prodID       = "-//ferueda//go-whoop//EN"
+	dateLayout   = "20060102T150405"
+	maxLineBytes = 75
+)
+
+// Calendar is a calendar of sleeps and workouts.
+type Calendar struct {
+	Name     string // Display name of the calendar. Optional.
+	Sleeps   []whoop.Sleep
+	Workouts []whoop.Workout
+}
+
+// event is a VEVENT of the calendar.
+type event struct {
+	uid         string
+	stamp       time.Time
+	start, end  time.Time
+	zone        *zone
+	summary     string
+	description string
+	categories  string
+}
+
+// zone is a fixed offset time zone.
+type zone struct {
+	id     string // TZID such as "UTC-0800", without colons as it is an unquoted parameter value.
+	offset int    // Seconds east of UTC.
+}
+
+// Write writes cal to w as an iCalendar object.
+//
+// Workouts are titled by their sport name, and described with their strain
+// and average and max heart rate once scored. Sleeps are titled "Sleep",
+// or "Nap" with a NAP category for naps. Records without a start or an end
+// time are skipped.
+func Write(w io.Writer, cal Calendar) error {
+	var events []event
+	for i := range cal.Sleeps {
+		if e, ok := sleepEvent(&cal.Sleeps[i]); ok {
+			events = append(events, e)
+		}
+	}
+	for i := range cal.Workouts {
+		if e, ok := workoutEvent(&cal.Workouts[i]); ok {
+			events = append(events, e)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].start.Before(events[j].start) })
+
+	zones := map[string]*zone{}
+	for _, e := range events {
+		if e.zone != nil {
+			zones[e.zone.id] = e.zone
+		}
+	}
+	ids := make([]string, 0, len(zones))
+	for id := range zones {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeLine(bw, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	line("CALSCALE", "GREGORIAN")
+	if cal.Name != "" {
+		line("X-WR-CALNAME", escape(cal.Name))
+	}
+	for _, id := range ids {
+		offset := formatOffset(zones[id].offset)
+		line("BEGIN", "VTIMEZONE")
+		line("TZID", id)
+		line("BEGIN", "STANDARD")
+		line("DTSTART", "19700101T000000")
+		line("TZOFFSETFROM", offset)
+		line("TZOFFSETTO", offset)
+		line("TZNAME", id)
+		line("END", "STANDARD")
+		line("END", "VTIMEZONE")
+	}
+	for _, e := range events {
+		line("BEGIN", "VEVENT")
+		line("UID", e.uid)
+		line("DTSTAMP", e.stamp.UTC().Format(dateLayout)+"Z")
+		if e.zone != nil {
+			loc := time.FixedZone(e.zone.id, e.zone.offset)
+			line("DTSTART;TZID="+e.zone.id, e.start.In(loc).Format(dateLayout))
+			line("DTEND;TZID="+e.zone.id, e.end.In(loc).Format(dateLayout))
+		} else {
+			line("DTSTART", e.start.UTC().Format(dateLayout)+"Z")
+			line("DTEND", e.end.UTC().Format(dateLayout)+"Z")
+		}
+		line("SUMMARY", escape(e.summary))
+		if e.description != "" {
+			line("DESCRIPTION", escape(e.description))
+		}
+		if e.categories != "" {
+			line("CATEGORIES", e.categories)
+		}
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func sleepEvent(s *whoop.Sleep) (event, bool) {
+	if s.Start == nil || s.End == nil {
+		return event{}, false
+	}
+	e := event{
+		uid:     fmt.Sprintf("sleep-%v@whoop", s.ID),
+		stamp:   stamp(s.UpdatedAt, s.CreatedAt, *s.Start),
+		start:   *s.Start,
+		end:     *s.End,
+		zone:    parseZone(s.TimezoneOffset),
+		summary: "Sleep",
+	}
+	if s.Nap {
+		e.summary = "Nap"
+		e.categories = "NAP"
+	}
+	if s.Scored() {
+		e.description = fmt.Sprintf("Sleep performance: %v%%\nSleep efficiency: %v%%",
+			round(s.Score.SleepPerformancePercentage), round(s.Score.SleepEfficiencyPercentage))
+	}
+	return e, true
+}
+
+func workoutEvent(w *whoop.Workout) (event, bool) {
+	if w.Start == nil || w.End == nil {
+		return event{}, false
+	}
+	e := event{
+		uid:        fmt.Sprintf("workout-%v@whoop", w.ID),
+		stamp:      stamp(w.UpdatedAt, w.CreatedAt, *w.Start),
+		start:      *w.Start,
+		end:        *w.End,
+		zone:       parseZone(w.TimezoneOffset),
+		summary:    w.Sport(),
+		categories: "WORKOUT",
+	}
+	if w.Scored() {
+		e.description = fmt.Sprintf("Strain: %v\nAverage heart rate: %v bpm\nMax heart rate: %v bpm",
+			round(w.Score.Strain), w.Score.AverageHeartRate, w.Score.MaxHeartRate)
+	}
+	return e, true
+}
+
+// stamp returns the first non-nil time of updated and created, or start.
+func stamp(updated, created *time.Time, start time.Time) time.Time {
+	if updated != nil {
+		return *updated
+	}
+	if created != nil {
+		return *created
+	}
+	return start
+}
+
+// round rounds v to one decimal.
+func round(v float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", v), ".0")
+}
+
+// parseZone parses an offset such as "-08:00" into a zone. It returns nil
+// for a nil, invalid or UTC offset.
+func parseZone(offset *string) *zone {
+	_, secs := time.Time{}.In(whoop.ParseOffset(offset)).Zone()
+	if secs == 0 {
+		return nil
+	}
+	return &zone{id: "UTC" + formatOffset(secs), offset: secs}
+}
+
+// formatOffset formats secs as a UTC offset such as "-0800".
+func formatOffset(secs int) string {
+	sign := '+'
+	if secs < 0 {
+		sign = '-'
+		secs = -secs
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, secs/3600, secs%3600/60)
+}
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// writeLine writes a content line terminated by CRLF, folding it into lines
+// of at most 75 octets without splitting UTF-8 sequences.
+func writeLine(w *bufio.Writer, s string) {
+	limit := maxLineBytes
+	for len(s) > limit {
+		i := limit
+		for i > 0 && !utf8.RuneStart(s[i]) {
+			i--
+		}
+		w.WriteString(s[:i])
+		w.WriteString("\r\n ")
+		s = s[i:]
+		limit = maxLineBytes - 1 // Continuation lines start with a space.
+	}
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
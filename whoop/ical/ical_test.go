@@ -0,0 +1,86 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func testWorkout() whoop.Workout {
+	start := time.Date(2022, 11, 27, 16, 0, 0, 0, time.UTC)
+	end := start.Add(45 * time.Minute)
+	w := whoop.Workout{ID: 7, Start: &start, End: &end, UpdatedAt: &end, TimezoneOffset: whooptest.Ptr("-08:00"), SportName: whooptest.Ptr("Running"), ScoreState: whooptest.Ptr("SCORED")}
+	w.Score.Strain = 12.54
+	w.Score.AverageHeartRate = 140
+	w.Score.MaxHeartRate = 175
+	return w
+}
+
+func testNap() whoop.Sleep {
+	start := time.Date(2022, 11, 27, 21, 0, 0, 0, time.UTC)
+	end := start.Add(30 * time.Minute)
+	return whoop.Sleep{ID: 3, Start: &start, End: &end, Nap: true, ScoreState: whooptest.Ptr("PENDING_SCORE")}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	err := Write(&buf, Calendar{
+		Name:     "Training",
+		Sleeps:   []whoop.Sleep{testNap(), {ID: 4}},
+		Workouts: []whoop.Workout{testWorkout()},
+	})
+	if err != nil {
+		t.Fatalf("Write(): expected nil error, got %v", err)
+	}
+
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//ferueda//go-whoop//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Training",
+		"BEGIN:VTIMEZONE",
+		"TZID:UTC-0800",
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:-0800",
+		"TZOFFSETTO:-0800",
+		"TZNAME:UTC-0800",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:workout-7@whoop",
+		"DTSTAMP:20221127T164500Z",
+		"DTSTART;TZID=UTC-0800:20221127T080000",
+		"DTEND;TZID=UTC-0800:20221127T084500",
+		"SUMMARY:Running",
+		`DESCRIPTION:Strain: 12.5\nAverage heart rate: 140 bpm\nMax heart rate: 175 `,
+		" bpm",
+		"CATEGORIES:WORKOUT",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:sleep-3@whoop",
+		"DTSTAMP:20221127T210000Z",
+		"DTSTART:20221127T210000Z",
+		"DTEND:20221127T213000Z",
+		"SUMMARY:Nap",
+		"CATEGORIES:NAP",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n") + "\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Write(): got\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestEscape(t *testing.T) {
+	if got, want := escape("a,b;c\\d\ne"), `a\,b\;c\\d\ne`; got != want {
+		t.Errorf("escape(): got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,195 @@
+package whoop
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UnknownFieldsError is returned in strict decoding mode when an API
+// response holds fields that are not part of the models.
+type UnknownFieldsError struct {
+	Fields []string // Paths of the unknown fields, e.g. "records[0].score.new_field".
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("whoop: response has unknown fields: %v", strings.Join(e.Fields, ", "))
+}
+
+// WithStrictDecoding sets whether the client returns an *UnknownFieldsError
+// when an API response holds fields that are not part of the models,
+// which is useful for contract tests that need to notice API changes.
+// It returns the client to allow chaining with NewClient.
+//
+// By default unknown fields are kept in the Extra field of the models.
+func (c *Client) WithStrictDecoding(strict bool) *Client {
+	c.strict = strict
+	return c
+}
+
+// extraFielder is implemented by responses which retain unknown fields.
+type extraFielder interface {
+	unknownFields() []string
+}
+
+// decode decodes the JSON value read from r into v, enforcing strict
+// decoding if enabled. In strict mode, every unknown field of the
+// response is reported, those of v itself followed by those retained by
+// the models it holds.
+func (c *Client) decode(r io.Reader, v any) error {
+	if !c.strict {
+		return json.NewDecoder(r).Decode(v)
+	}
+	var data json.RawMessage
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	extra := make(map[string]json.RawMessage)
+	// Models implementing json.Unmarshaler retain their own unknown fields.
+	if t := reflect.TypeOf(v); t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct && !t.Implements(unmarshalerType) {
+		collectExtra(data, t.Elem(), "", extra)
+	}
+	fields := extraKeys("", extra)
+	if e, ok := v.(extraFielder); ok {
+		fields = append(fields, e.unknownFields()...)
+	}
+	if len(fields) > 0 {
+		return &UnknownFieldsError{Fields: fields}
+	}
+	return nil
+}
+
+// unmarshalWithExtra unmarshals the JSON object data into v, a pointer to
+// a struct, and returns the fields of data which don't match a field of v,
+// including those of nested objects, keyed by their dotted path.
+func unmarshalWithExtra(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	extra := make(map[string]json.RawMessage)
+	collectExtra(data, reflect.TypeOf(v).Elem(), "", extra)
+	if len(extra) == 0 {
+		return nil, nil
+	}
+	return extra, nil
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// collectExtra adds the fields of the JSON object data which don't match
+// a field of the struct type t to extra.
+func collectExtra(data []byte, t reflect.Type, prefix string, extra map[string]json.RawMessage) {
+	var obj map[string]json.RawMessage
+	if json.Unmarshal(data, &obj) != nil {
+		return
+	}
+	fields := jsonFields(t)
+	for key, raw := range obj {
+		f, ok := fields[strings.ToLower(key)]
+		if !ok {
+			extra[prefix+key] = raw
+			continue
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && ft != timeType && !reflect.PointerTo(ft).Implements(unmarshalerType) {
+			collectExtra(raw, ft, prefix+key+".", extra)
+		}
+	}
+}
+
+// jsonFields returns the fields of the struct type t keyed by their
+// lower case JSON name, as encoding/json matches names case-insensitively.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f
+	}
+	return fields
+}
+
+// marshalWithExtra marshals v, a struct, adding the fields of extra
+// at their dotted path.
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	for path, raw := range extra {
+		if obj, err = setPath(obj, strings.Split(path, "."), raw); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(obj); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// setPath sets the value at path in obj, creating nested objects as needed.
+func setPath(obj map[string]json.RawMessage, path []string, raw json.RawMessage) (map[string]json.RawMessage, error) {
+	if obj == nil {
+		obj = make(map[string]json.RawMessage)
+	}
+	if len(path) == 1 {
+		obj[path[0]] = raw
+		return obj, nil
+	}
+	var child map[string]json.RawMessage
+	if existing, ok := obj[path[0]]; ok && string(existing) != "null" {
+		if err := json.Unmarshal(existing, &child); err != nil {
+			return nil, err
+		}
+	}
+	child, err := setPath(child, path[1:], raw)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(child)
+	if err != nil {
+		return nil, err
+	}
+	obj[path[0]] = data
+	return obj, nil
+}
+
+// extraKeys returns the sorted keys of extra, prefixed by prefix.
+func extraKeys(prefix string, extra map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, prefix+k)
+	}
+	sort.Strings(keys)
+	return keys
+}
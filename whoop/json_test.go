@@ -0,0 +1,101 @@
+package whoop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestCycle_unknownFields(t *testing.T) {
+	data := `{"id":1,"user_id":2,"new_field":"x","score":{"strain":4.5,"new_score":{"a":1}}}`
+
+	var c Cycle
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatalf("Cycle.UnmarshalJSON(): expected nil error, got %v", err)
+	}
+	if c.ID != 1 || c.Score.Strain != 4.5 {
+		t.Errorf("Cycle.UnmarshalJSON(): got %+v", c)
+	}
+	if got, want := fmt.Sprint(c.unknownFields()), "[new_field score.new_score]"; got != want {
+		t.Errorf("Cycle.UnmarshalJSON(): Extra keys are %v, want %v", got, want)
+	}
+	if got := string(c.Extra["score.new_score"]); got != `{"a":1}` {
+		t.Errorf("Cycle.UnmarshalJSON(): Extra[score.new_score] is %v, want {\"a\":1}", got)
+	}
+
+	out, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("Cycle.MarshalJSON(): expected nil error, got %v", err)
+	}
+	var roundTrip Cycle
+	json.Unmarshal(out, &roundTrip)
+	if fmt.Sprint(roundTrip.unknownFields()) != fmt.Sprint(c.unknownFields()) || roundTrip.Score.Strain != 4.5 {
+		t.Errorf("Cycle.MarshalJSON(): unknown fields lost in round trip, got %s", out)
+	}
+}
+
+func TestModels_noUnknownFields(t *testing.T) {
+	testCases := []struct {
+		data string
+		v    interface {
+			unknownFields() []string
+		}
+	}{
+		{`{"id":1,"score":{"stage_summary":{"total_in_bed_time_milli":1},"sleep_needed":{"baseline_milli":1}}}`, &Sleep{}},
+		{`{"cycle_id":1,"score":{"user_calibrating":true}}`, &Recovery{}},
+		{`{"id":1,"sport_id":1,"score":{"zone_duration":{"zone_one_milli":1}}}`, &Workout{}},
+		{`{"user_id":1,"EMAIL":"a@b.c"}`, &UserProfile{}},
+		{`{"height_meter":1.8}`, &BodyMeasurement{}},
+	}
+	for _, test := range testCases {
+		if err := json.Unmarshal([]byte(test.data), test.v); err != nil {
+			t.Fatalf("json.Unmarshal(%T): expected nil error, got %v", test.v, err)
+		}
+		if got := test.v.unknownFields(); len(got) != 0 {
+			t.Errorf("json.Unmarshal(%T): expected no unknown fields, got %v", test.v, got)
+		}
+	}
+}
+
+func TestClient_strictDecoding(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+	client.WithStrictDecoding(true)
+
+	mux.HandleFunc("/"+apiVersion+sleepEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"records": [{"id": 1}, {"id": 2, "score": {"new_metric": 1}}], "next_token": null}`)
+	})
+	mux.HandleFunc("/"+apiVersion+cycleEndpoint, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"records": [{"id": 1, "new_metric": 1}], "next_token": null, "total": 0, "page": 1}`)
+	})
+
+	ctx := context.Background()
+	_, err := client.Sleep.ListAll(ctx, nil)
+	var unknown *UnknownFieldsError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Sleep.ListAll(): expected UnknownFieldsError, got %v", err)
+	}
+	if got, want := fmt.Sprint(unknown.Fields), "[records[1].score.new_metric]"; got != want {
+		t.Errorf("Sleep.ListAll(): unknown fields are %v, want %v", got, want)
+	}
+
+	_, err = client.Cycle.ListAll(ctx, nil)
+	if !errors.As(err, &unknown) {
+		t.Fatalf("Cycle.ListAll(): expected UnknownFieldsError, got %v", err)
+	}
+	if got, want := fmt.Sprint(unknown.Fields), "[page total records[0].new_metric]"; got != want {
+		t.Errorf("Cycle.ListAll(): unknown fields are %v, want %v", got, want)
+	}
+
+	client.WithStrictDecoding(false)
+	resp, err := client.Sleep.ListAll(ctx, nil)
+	if err != nil {
+		t.Fatalf("Sleep.ListAll(): expected nil error, got %v", err)
+	}
+	if string(resp.Records[1].Extra["score.new_metric"]) != "1" {
+		t.Errorf("Sleep.ListAll(): expected unknown field kept in Extra, got %v", resp.Records[1].Extra)
+	}
+}
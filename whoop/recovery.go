@@ -2,6 +2,7 @@ package whoop
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -35,6 +36,41 @@ type Recovery struct {
 		Spo2Percentage   float64 `json:"spo2_percentage,omitempty"`    // Percentage of oxygen in the user's blood.
 		SkinTempCelsius  float64 `json:"skin_temp_celsius,omitempty"`  // Skin temperature, in Celsius.
 	} `json:"score,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields returned by the API that are not part of the model, keyed by their dotted path.
+}
+
+// UnmarshalJSON unmarshals data into r, keeping unknown fields in r.Extra.
+func (r *Recovery) UnmarshalJSON(data []byte) error {
+	type recovery Recovery
+	var v recovery
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*r = Recovery(v)
+	r.Extra = extra
+	return nil
+}
+
+// MarshalJSON marshals r, including the unknown fields in r.Extra.
+func (r Recovery) MarshalJSON() ([]byte, error) {
+	type recovery Recovery
+	return marshalWithExtra(recovery(r), r.Extra)
+}
+
+func (r Recovery) unknownFields() []string {
+	return extraKeys("", r.Extra)
+}
+
+// Scored reports whether the recovery has been scored.
+func (r Recovery) Scored() bool {
+	return r.ScoreState != nil && *r.ScoreState == "SCORED"
+}
+
+// settled reports whether the recovery has been scored.
+func (r Recovery) settled() bool {
+	return r.Scored()
 }
 
 // GetOneByCycleId retrieves a single recovery record for the specified cycle id.
@@ -54,6 +90,26 @@ type RecoveryListAllResp struct {
 	NextToken *string    `json:"next_token"`
 }
 
+func (r RecoveryListAllResp) unknownFields() []string {
+	var fields []string
+	for i, rec := range r.Records {
+		fields = append(fields, extraKeys(fmt.Sprintf("records[%v].", i), rec.Extra)...)
+	}
+	return fields
+}
+
+func (RecoveryListAllResp) page() {}
+
+// settled reports whether every record in the page has settled.
+func (r RecoveryListAllResp) settled() bool {
+	for _, rec := range r.Records {
+		if !rec.settled() {
+			return false
+		}
+	}
+	return len(r.Records) > 0
+}
+
 // ListAll lists all recovery records for the authenticated user.
 // Results are paginated and sorted by start time in descending order.
 //
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop/analytics"
+)
+
+// topSports is the number of sports listed in a report.
+const topSports = 3
+
+// missing is shown in place of missing values.
+const missing = "-"
+
+// row is a line of the summary table shared by the Markdown and HTML
+// reports.
+type row struct {
+	Label, Current, Previous, Change string
+}
+
+// title returns the title of r.
+func (r *Report) title() string {
+	t := "Weekly report"
+	if r.Period == Month {
+		t = "Monthly report"
+	}
+	if r.Name != "" {
+		t += " for " + r.Name
+	}
+	return t
+}
+
+// dates returns the dates covered by r.
+func (r *Report) dates() string {
+	if r.Period == Month {
+		return r.Start.Format("January 2006")
+	}
+	return r.Start.Format("Mon 2006-01-02") + " to " + r.End.AddDate(0, 0, -1).Format("Mon 2006-01-02")
+}
+
+// periodName returns the names of the current and previous periods.
+func (r *Report) periodName() (string, string) {
+	if r.Period == Month {
+		return "This month", "Previous month"
+	}
+	return "This week", "Previous week"
+}
+
+// rows returns the summary table of r.
+func (r *Report) rows() []row {
+	cur, prev := r.Summary, r.Previous
+	return []row{
+		{
+			"Average recovery",
+			recovery(cur.Recovery, cur.RecoveryDays > 0),
+			recovery(prev.Recovery, prev.RecoveryDays > 0),
+			change(cur.Recovery, prev.Recovery, cur.RecoveryDays > 0 && prev.RecoveryDays > 0, "%+.0f"),
+		},
+		{
+			"Green / yellow / red days",
+			fmt.Sprintf("%d / %d / %d", cur.Green, cur.Yellow, cur.Red),
+			fmt.Sprintf("%d / %d / %d", prev.Green, prev.Yellow, prev.Red),
+			"",
+		},
+		{
+			"Total strain",
+			number(cur.Strain, cur.StrainDays > 0),
+			number(prev.Strain, prev.StrainDays > 0),
+			change(cur.Strain, prev.Strain, cur.StrainDays > 0 && prev.StrainDays > 0, "%+.1f"),
+		},
+		{
+			"Average strain",
+			number(cur.AverageStrain, cur.StrainDays > 0),
+			number(prev.AverageStrain, prev.StrainDays > 0),
+			change(cur.AverageStrain, prev.AverageStrain, cur.StrainDays > 0 && prev.StrainDays > 0, "%+.1f"),
+		},
+		{
+			"Sleep performance",
+			percent(cur.SleepPerformance, cur.SleepDays > 0),
+			percent(prev.SleepPerformance, prev.SleepDays > 0),
+			change(cur.SleepPerformance, prev.SleepPerformance, cur.SleepDays > 0 && prev.SleepDays > 0, "%+.0f"),
+		},
+		{
+			"Average sleep",
+			duration(cur.Sleep, cur.SleepDays > 0),
+			duration(prev.Sleep, prev.SleepDays > 0),
+			durationChange(cur.Sleep, prev.Sleep, cur.SleepDays > 0 && prev.SleepDays > 0),
+		},
+		{
+			"Workouts",
+			fmt.Sprint(cur.Workouts),
+			fmt.Sprint(prev.Workouts),
+			fmt.Sprintf("%+d", cur.Workouts-prev.Workouts),
+		},
+		{
+			"Workout time",
+			duration(cur.WorkoutTime, true),
+			duration(prev.WorkoutTime, true),
+			durationChange(cur.WorkoutTime, prev.WorkoutTime, true),
+		},
+	}
+}
+
+// top returns the first topSports sports of s.
+func top(s []SportTotal) []SportTotal {
+	if len(s) > topSports {
+		return s[:topSports]
+	}
+	return s
+}
+
+func recovery(v float64, ok bool) string {
+	if !ok {
+		return missing
+	}
+	return fmt.Sprintf("%.0f%% (%s)", v, analytics.RecoveryBand(v))
+}
+
+func percent(v float64, ok bool) string {
+	if !ok {
+		return missing
+	}
+	return fmt.Sprintf("%.0f%%", v)
+}
+
+func number(v float64, ok bool) string {
+	if !ok {
+		return missing
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+func change(cur, prev float64, ok bool, format string) string {
+	if !ok {
+		return missing
+	}
+	return fmt.Sprintf(format, cur-prev)
+}
+
+// duration formats d as hours and minutes, such as "7h 32m".
+func duration(d time.Duration, ok bool) string {
+	if !ok {
+		return missing
+	}
+	d = d.Round(time.Minute)
+	if d < 0 {
+		return "-" + duration(-d, true)
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return fmt.Sprintf("%dh %dm", d/time.Hour, d%time.Hour/time.Minute)
+}
+
+func durationChange(cur, prev time.Duration, ok bool) string {
+	if !ok {
+		return missing
+	}
+	if d := cur - prev; d < 0 {
+		return duration(d, true)
+	}
+	return "+" + duration(cur-prev, true)
+}
+
+// dayValues returns the formatted metrics of d.
+func dayValues(d DayRow) (recov, strain, performance, sleep string) {
+	recov, strain, performance, sleep = missing, missing, missing, missing
+	if d.Recovery != nil {
+		recov = recovery(*d.Recovery, true)
+	}
+	if d.Strain != nil {
+		strain = number(*d.Strain, true)
+	}
+	if d.SleepPerformance != nil {
+		performance = percent(*d.SleepPerformance, true)
+		sleep = duration(d.Sleep, true)
+	}
+	return recov, strain, performance, sleep
+}
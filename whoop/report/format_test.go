@@ -0,0 +1,46 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{42*time.Minute + 20*time.Second, "42m"},
+		{7*time.Hour + 32*time.Minute, "7h 32m"},
+		{-90 * time.Minute, "-1h 30m"},
+	}
+	for _, tt := range tests {
+		if got := duration(tt.d, true); got != tt.want {
+			t.Errorf("duration(%v): got %q, want %q", tt.d, got, tt.want)
+		}
+	}
+	if got := duration(time.Hour, false); got != missing {
+		t.Errorf("duration(): got %q for a missing value, want %q", got, missing)
+	}
+}
+
+func TestDurationChange(t *testing.T) {
+	if got, want := durationChange(8*time.Hour, 7*time.Hour+30*time.Minute, true), "+30m"; got != want {
+		t.Errorf("durationChange(): got %q, want %q", got, want)
+	}
+	if got, want := durationChange(7*time.Hour, 8*time.Hour, true), "-1h 0m"; got != want {
+		t.Errorf("durationChange(): got %q, want %q", got, want)
+	}
+}
+
+func TestReport_rows(t *testing.T) {
+	r := &Report{Summary: Summary{Recovery: 70, RecoveryDays: 3, Workouts: 2}, Previous: Summary{Workouts: 3}}
+	rows := r.rows()
+	if got := rows[0]; got.Current != "70% (green)" || got.Previous != missing || got.Change != missing {
+		t.Errorf("rows(): got %+v, want a recovery without previous value", got)
+	}
+	if got := rows[6]; got.Change != "-1" {
+		t.Errorf("rows(): got %+v, want a change of -1 workouts", got)
+	}
+}
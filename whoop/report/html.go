@@ -0,0 +1,164 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/ferueda/go-whoop/whoop/analytics"
+	"github.com/ferueda/go-whoop/whoop/chart"
+)
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; color: #222; max-width: 680px; margin: 2em auto; padding: 0 1em; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { padding: 4px 8px; border-bottom: 1px solid #eee; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+figure { margin: 1em 0; }
+svg { max-width: 100%; height: auto; }
+.green { color: #0a8f02; } .yellow { color: #a68f00; } .red { color: #d0001f; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Dates}}</p>
+<h2>Summary</h2>
+<table>
+<tr><th>Metric</th><th>{{.Current}}</th><th>{{.Previous}}</th><th>Change</th></tr>
+{{range .Rows}}<tr><td>{{.Label}}</td><td>{{.Current}}</td><td>{{.Previous}}</td><td>{{.Change}}</td></tr>
+{{end}}</table>
+{{range .Charts}}<figure>
+{{.}}
+</figure>
+{{end}}<h2>Top sports</h2>
+{{range .Sports}}<h3>By {{.By}}</h3>
+{{if .Sports}}<table>
+<tr><th>Sport</th><th>Workouts</th><th>Time</th><th>Strain</th></tr>
+{{range .Sports}}<tr><td>{{.Sport}}</td><td>{{.Workouts}}</td><td>{{.Time}}</td><td>{{.Strain}}</td></tr>
+{{end}}</table>
+{{else}}<p>No workouts.</p>
+{{end}}{{end}}<h2>Best and worst days</h2>
+{{if .Best}}<ul>
+<li>Best: {{.Best}}</li>
+<li>Worst: {{.Worst}}</li>
+</ul>
+{{else}}<p>No recoveries.</p>
+{{end}}<h2>Days</h2>
+<table>
+<tr><th>Day</th><th>Recovery</th><th>Strain</th><th>Sleep performance</th><th>Sleep</th><th>Workouts</th></tr>
+{{range .Days}}<tr><td>{{.Date}}</td><td{{with .Band}} class="{{.}}"{{end}}>{{.Recovery}}</td><td>{{.Strain}}</td><td>{{.SleepPerformance}}</td><td>{{.Sleep}}</td><td>{{.Workouts}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// htmlSport is a sport in the HTML template.
+type htmlSport struct {
+	Sport, Time, Strain string
+	Workouts            int
+}
+
+// htmlDay is a day in the HTML template.
+type htmlDay struct {
+	Date, Band, Recovery, Strain, SleepPerformance, Sleep string
+	Workouts                                              int
+}
+
+// WriteHTML writes r to w as a self-contained HTML page, with inline SVG
+// charts of the daily recovery, strain and sleep performance.
+func (r *Report) WriteHTML(w io.Writer) error {
+	type sports struct {
+		By     string
+		Sports []htmlSport
+	}
+	data := struct {
+		Title, Dates, Current, Previous string
+		Rows                            []row
+		Charts                          []template.HTML
+		Sports                          []sports
+		Best, Worst                     string
+		Days                            []htmlDay
+	}{
+		Title: r.title(),
+		Dates: r.dates(),
+		Rows:  r.rows(),
+	}
+	data.Current, data.Previous = r.periodName()
+	charts, err := r.charts()
+	if err != nil {
+		return err
+	}
+	for _, svg := range charts {
+		data.Charts = append(data.Charts, template.HTML(svg)) // Package chart escapes text and attribute values.
+	}
+	for _, by := range []struct {
+		name   string
+		sports []SportTotal
+	}{
+		{"time", r.Summary.SportsByTime},
+		{"strain", r.Summary.SportsByStrain},
+	} {
+		s := sports{By: by.name}
+		for _, t := range top(by.sports) {
+			s.Sports = append(s.Sports, htmlSport{Sport: t.Sport, Workouts: t.Workouts, Time: duration(t.Time, true), Strain: number(t.Strain, true)})
+		}
+		data.Sports = append(data.Sports, s)
+	}
+	if r.Best != nil {
+		data.Best, data.Worst = describe(r.Best), describe(r.Worst)
+	}
+	for _, d := range r.Days {
+		day := htmlDay{Date: d.Date.Format("Mon 2006-01-02"), Workouts: d.Workouts}
+		day.Recovery, day.Strain, day.SleepPerformance, day.Sleep = dayValues(d)
+		if d.Recovery != nil {
+			day.Band = string(analytics.RecoveryBand(*d.Recovery))
+		}
+		data.Days = append(data.Days, day)
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+// charts returns the recovery, strain and sleep performance charts of r,
+// with a bar per day.
+func (r *Report) charts() ([]string, error) {
+	var recovery, strain, sleep []chart.Bar
+	for _, d := range r.Days {
+		label := d.Date.Format("Mon 2")
+		if r.Period == Month {
+			label = d.Date.Format("2")
+		}
+		rb, sb, pb := chart.Bar{Label: label}, chart.Bar{Label: label}, chart.Bar{Label: label}
+		if d.Recovery != nil {
+			rb.Segments = []chart.Segment{{Value: *d.Recovery, Color: chart.BandColor(analytics.RecoveryBand(*d.Recovery))}}
+		}
+		if d.Strain != nil {
+			sb.Segments = []chart.Segment{{Value: *d.Strain, Color: chart.StrainColor}}
+		}
+		if d.SleepPerformance != nil {
+			pb.Segments = []chart.Segment{{Value: *d.SleepPerformance, Color: chart.LightColor}}
+		}
+		recovery, strain, sleep = append(recovery, rb), append(strain, sb), append(sleep, pb)
+	}
+
+	var charts []string
+	for _, c := range []struct {
+		opts chart.Options
+		bars []chart.Bar
+	}{
+		{chart.Options{Title: "Recovery", YLabel: "%", YMax: 100, Height: 200}, recovery},
+		{chart.Options{Title: "Strain", YMax: 21, Height: 200}, strain},
+		{chart.Options{Title: "Sleep performance", YLabel: "%", YMax: 100, Height: 200}, sleep},
+	} {
+		var b strings.Builder
+		if err := chart.Bars(&b, c.opts, c.bars); err != nil {
+			return nil, err
+		}
+		charts = append(charts, b.String())
+	}
+	return charts, nil
+}
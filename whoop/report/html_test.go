@@ -0,0 +1,37 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+)
+
+func TestReport_WriteHTML(t *testing.T) {
+	data := testData()
+	data.Profile.FirstName = whooptest.Ptr("<Jane>")
+	var b strings.Builder
+	if err := New(data, Week, day(17)).WriteHTML(&b); err != nil {
+		t.Fatalf("WriteHTML(): %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"<title>Weekly report for &lt;Jane&gt; Doe</title>",
+		"<tr><td>Average recovery</td><td>81% (green)</td><td>45% (yellow)</td><td>&#43;36</td></tr>",
+		"<figure>\n<svg ",
+		"<title>Recovery</title>",
+		`<td class="yellow">65% (yellow)</td>`,
+		"<tr><td>Running</td><td>4</td><td>4h 0m</td><td>40.0</td></tr>",
+		"<li>Best: Sun 2022-11-20, recovery 95% (green), strain 20.0, sleep 7h 0m</li>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteHTML(): got\n%s\nwant it to contain\n%s", got, want)
+		}
+	}
+	if n := strings.Count(got, "<svg "); n != 3 {
+		t.Errorf("WriteHTML(): got %d charts, want 3", n)
+	}
+	if strings.Contains(got, "<Jane>") {
+		t.Errorf("WriteHTML(): got unescaped name in\n%s", got)
+	}
+}
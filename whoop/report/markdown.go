@@ -0,0 +1,69 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown writes r to w in Markdown.
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	cur, prev := r.periodName()
+
+	fmt.Fprintf(bw, "# %s\n\n%s\n\n", escapeMarkdown(r.title()), r.dates())
+
+	fmt.Fprintf(bw, "## Summary\n\n| Metric | %s | %s | Change |\n| --- | ---: | ---: | ---: |\n", cur, prev)
+	for _, row := range r.rows() {
+		fmt.Fprintf(bw, "| %s | %s | %s | %s |\n", row.Label, row.Current, row.Previous, row.Change)
+	}
+
+	fmt.Fprint(bw, "\n## Top sports\n")
+	for _, t := range []struct {
+		by     string
+		sports []SportTotal
+	}{
+		{"time", r.Summary.SportsByTime},
+		{"strain", r.Summary.SportsByStrain},
+	} {
+		fmt.Fprintf(bw, "\n### By %s\n\n", t.by)
+		if len(t.sports) == 0 {
+			fmt.Fprint(bw, "No workouts.\n")
+			continue
+		}
+		fmt.Fprint(bw, "| Sport | Workouts | Time | Strain |\n| --- | ---: | ---: | ---: |\n")
+		for _, s := range top(t.sports) {
+			fmt.Fprintf(bw, "| %s | %d | %s | %.1f |\n", escapeMarkdown(s.Sport), s.Workouts, duration(s.Time, true), s.Strain)
+		}
+	}
+
+	fmt.Fprint(bw, "\n## Best and worst days\n\n")
+	if r.Best == nil {
+		fmt.Fprint(bw, "No recoveries.\n")
+	} else {
+		fmt.Fprintf(bw, "- Best: %s\n- Worst: %s\n", describe(r.Best), describe(r.Worst))
+	}
+
+	fmt.Fprint(bw, "\n## Days\n\n| Day | Recovery | Strain | Sleep performance | Sleep | Workouts |\n| --- | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, d := range r.Days {
+		recov, strain, performance, sleep := dayValues(d)
+		fmt.Fprintf(bw, "| %s | %s | %s | %s | %s | %d |\n", d.Date.Format("Mon 2006-01-02"), recov, strain, performance, sleep, d.Workouts)
+	}
+	return bw.Flush()
+}
+
+// describe returns a description of d for the best and worst days.
+func describe(d *DayRow) string {
+	recov, strain, _, sleep := dayValues(*d)
+	return fmt.Sprintf("%s, recovery %s, strain %s, sleep %s", d.Date.Format("Mon 2006-01-02"), recov, strain, sleep)
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`,
+)
+
+// escapeMarkdown escapes the Markdown syntax in s.
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReport_WriteMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := New(testData(), Week, day(17)).WriteMarkdown(&b); err != nil {
+		t.Fatalf("WriteMarkdown(): %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"# Weekly report for Jane Doe\n\nMon 2022-11-14 to Sun 2022-11-20\n",
+		"| Metric | This week | Previous week | Change |\n",
+		"| Average recovery | 81% (green) | 45% (yellow) | +36 |\n",
+		"| Green / yellow / red days | 5 / 1 / 0 | 0 / 6 / 1 |  |\n",
+		"| Total strain | 119.0 | 70.0 | +49.0 |\n",
+		"| Average sleep | 7h 0m | 7h 0m | +0m |\n",
+		"| Workout time | 6h 0m | 3h 0m | +3h 0m |\n",
+		"### By time\n\n| Sport | Workouts | Time | Strain |\n| --- | ---: | ---: | ---: |\n| Running | 4 | 4h 0m | 40.0 |\n| Cycling | 1 | 2h 0m | 8.0 |\n",
+		"- Best: Sun 2022-11-20, recovery 95% (green), strain 20.0, sleep 7h 0m\n",
+		"- Worst: Mon 2022-11-14, recovery 65% (yellow), strain 14.0, sleep 7h 0m\n",
+		"| Wed 2022-11-16 | - | 16.0 | 90% | 7h 0m | 1 |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteMarkdown(): got\n%s\nwant it to contain\n%s", got, want)
+		}
+	}
+}
+
+func TestReport_WriteMarkdown_empty(t *testing.T) {
+	var b strings.Builder
+	if err := New(Data{}, Month, day(17)).WriteMarkdown(&b); err != nil {
+		t.Fatalf("WriteMarkdown(): %v", err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"# Monthly report\n\nNovember 2022\n",
+		"| Average recovery | - | - | - |\n",
+		"### By time\n\nNo workouts.\n",
+		"## Best and worst days\n\nNo recoveries.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteMarkdown(): got\n%s\nwant it to contain\n%s", got, want)
+		}
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	if got, want := escapeMarkdown("a|b *c*"), `a\|b \*c\*`; got != want {
+		t.Errorf("escapeMarkdown(): got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,330 @@
+// Package report generates weekly and monthly summaries of a member's
+// WHOOP data, in Markdown and in self-contained HTML with inline SVG
+// charts.
+//
+// Records are grouped by day as in package analytics: cycles, sleeps and
+// recoveries belong to the local date twelve hours after their cycle or
+// sleep starts, and workouts to the local date on which they start.
+package report
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/analytics"
+)
+
+// Period is the length of a report.
+type Period string
+
+// Periods of a report.
+const (
+	Week  Period = "week"  // From Monday to Sunday.
+	Month Period = "month" // A calendar month.
+)
+
+// Start returns the first day of the period containing date.
+func (p Period) Start(date time.Time) time.Time {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if p == Month {
+		return date.AddDate(0, 0, 1-date.Day())
+	}
+	return analytics.WeekStart(date)
+}
+
+// add returns the start of the period n periods after the one starting
+// on start.
+func (p Period) add(start time.Time, n int) time.Time {
+	if p == Month {
+		return start.AddDate(0, n, 0)
+	}
+	return start.AddDate(0, 0, 7*n)
+}
+
+// Data holds the records a report is generated from. Records outside the
+// reported and previous periods are ignored.
+type Data struct {
+	Profile    *whoop.UserProfile
+	Cycles     []whoop.Cycle
+	Recoveries []whoop.Recovery
+	Sleeps     []whoop.Sleep
+	Workouts   []whoop.Workout
+}
+
+// Report is the summary of a period, compared to the previous period.
+type Report struct {
+	Name     string // Name of the member, if known.
+	Period   Period
+	Start    time.Time // First day of the period.
+	End      time.Time // First day after the period.
+	Days     []DayRow  // Every day of the period, in chronological order.
+	Summary  Summary
+	Previous Summary // Summary of the previous period.
+	Best     *DayRow // Day with the highest recovery, if any.
+	Worst    *DayRow // Day with the lowest recovery, if any.
+}
+
+// DayRow holds the metrics of a day. Missing metrics are nil.
+type DayRow struct {
+	Date             time.Time
+	Recovery         *float64 // Recovery score.
+	Strain           *float64 // Strain of the cycle.
+	SleepPerformance *float64 // Sleep performance of the night, excluding naps.
+	Sleep            time.Duration
+	Workouts         int
+}
+
+// Summary holds the totals and averages of a period.
+type Summary struct {
+	Recovery     float64 // Average recovery score.
+	RecoveryDays int     // Days with a recovery.
+	Green        int     // Days in the green band.
+	Yellow       int     // Days in the yellow band.
+	Red          int     // Days in the red band.
+
+	Strain        float64 // Total strain of the cycles.
+	AverageStrain float64
+	StrainDays    int // Days with a cycle.
+
+	SleepPerformance float64       // Average sleep performance, excluding naps.
+	Sleep            time.Duration // Average time asleep per night, excluding naps.
+	SleepDays        int           // Days with a night of sleep.
+
+	Workouts       int
+	WorkoutTime    time.Duration
+	SportsByTime   []SportTotal // Sorted by decreasing time.
+	SportsByStrain []SportTotal // Sorted by decreasing strain.
+}
+
+// SportTotal holds the totals of the workouts of a sport.
+type SportTotal struct {
+	Sport    string
+	Workouts int
+	Time     time.Duration
+	Strain   float64 // Total strain of the workouts.
+}
+
+// New generates the report of the period containing date from data.
+// Unscored records and recoveries of calibrating members are skipped.
+func New(data Data, period Period, date time.Time) *Report {
+	start := period.Start(date)
+	r := &Report{
+		Name:   name(data.Profile),
+		Period: period,
+		Start:  start,
+		End:    period.add(start, 1),
+	}
+	days := collect(data)
+	r.Days = between(days, r.Start, r.End)
+	r.Summary = summarize(r.Days, data.Workouts, r.Start, r.End)
+	prev := period.add(start, -1)
+	r.Previous = summarize(between(days, prev, r.Start), data.Workouts, prev, r.Start)
+
+	for i := range r.Days {
+		d := &r.Days[i]
+		if d.Recovery == nil {
+			continue
+		}
+		if r.Best == nil || *d.Recovery > *r.Best.Recovery {
+			r.Best = d
+		}
+		if r.Worst == nil || *d.Recovery < *r.Worst.Recovery {
+			r.Worst = d
+		}
+	}
+	return r
+}
+
+func name(p *whoop.UserProfile) string {
+	if p == nil {
+		return ""
+	}
+	var parts []string
+	for _, s := range []*string{p.FirstName, p.LastName} {
+		if s != nil && *s != "" {
+			parts = append(parts, *s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// mean accumulates the values of a metric on a day.
+type mean struct {
+	sum float64
+	n   int
+}
+
+func (m *mean) add(v float64) {
+	m.sum += v
+	m.n++
+}
+
+// value returns the mean, or nil if there are no values.
+func (m *mean) value() *float64 {
+	if m.n == 0 {
+		return nil
+	}
+	v := m.sum / float64(m.n)
+	return &v
+}
+
+// collect returns the metrics of every day with data, by date. Several
+// records on the same day are averaged, except times asleep which are
+// summed.
+func collect(data Data) map[time.Time]*DayRow {
+	days := map[time.Time]*DayRow{}
+	type means struct{ recovery, strain, sleep mean }
+	acc := map[time.Time]*means{}
+	row := func(date time.Time) (*DayRow, *means) {
+		d, ok := days[date]
+		if !ok {
+			d = &DayRow{Date: date}
+			days[date] = d
+			acc[date] = &means{}
+		}
+		return d, acc[date]
+	}
+
+	cycles := map[int]*whoop.Cycle{}
+	for i := range data.Cycles {
+		c := &data.Cycles[i]
+		cycles[c.ID] = c
+		date, ok := analytics.CycleDate(c)
+		if !ok || !c.Scored() {
+			continue
+		}
+		_, m := row(date)
+		m.strain.add(c.Score.Strain)
+	}
+	for i := range data.Recoveries {
+		rec := &data.Recoveries[i]
+		if !rec.Scored() || rec.Score.UserCalibrating {
+			continue
+		}
+		if date, ok := analytics.RecoveryDate(rec, cycles); ok {
+			_, m := row(date)
+			m.recovery.add(rec.Score.RecoveryScore)
+		}
+	}
+	for i := range data.Sleeps {
+		s := &data.Sleeps[i]
+		if s.Nap || !s.Scored() {
+			continue
+		}
+		if date, ok := analytics.SleepDate(s); ok {
+			d, m := row(date)
+			m.sleep.add(s.Score.SleepPerformancePercentage)
+			d.Sleep += s.TimeAsleep()
+		}
+	}
+	for i := range data.Workouts {
+		w := &data.Workouts[i]
+		if !w.Scored() || w.Start == nil {
+			continue
+		}
+		d, _ := row(analytics.Date(*w.Start, w.TimezoneOffset))
+		d.Workouts++
+	}
+
+	for date, d := range days {
+		m := acc[date]
+		d.Recovery, d.Strain, d.SleepPerformance = m.recovery.value(), m.strain.value(), m.sleep.value()
+	}
+	return days
+}
+
+// between returns a row for every day from start to end, excluded.
+func between(days map[time.Time]*DayRow, start, end time.Time) []DayRow {
+	var rows []DayRow
+	for date := start; date.Before(end); date = date.AddDate(0, 0, 1) {
+		if d, ok := days[date]; ok {
+			rows = append(rows, *d)
+		} else {
+			rows = append(rows, DayRow{Date: date})
+		}
+	}
+	return rows
+}
+
+// summarize returns the summary of the days, and of the workouts starting
+// on a day from start to end, excluded.
+func summarize(days []DayRow, workouts []whoop.Workout, start, end time.Time) Summary {
+	var s Summary
+	var recovery, performance mean
+	var sleep time.Duration
+	for _, d := range days {
+		if d.Recovery != nil {
+			recovery.add(*d.Recovery)
+			switch analytics.RecoveryBand(*d.Recovery) {
+			case analytics.Green:
+				s.Green++
+			case analytics.Yellow:
+				s.Yellow++
+			default:
+				s.Red++
+			}
+		}
+		if d.Strain != nil {
+			s.Strain += *d.Strain
+			s.StrainDays++
+		}
+		if d.SleepPerformance != nil {
+			performance.add(*d.SleepPerformance)
+			sleep += d.Sleep
+		}
+	}
+	s.RecoveryDays = recovery.n
+	if v := recovery.value(); v != nil {
+		s.Recovery = *v
+	}
+	if s.StrainDays > 0 {
+		s.AverageStrain = s.Strain / float64(s.StrainDays)
+	}
+	s.SleepDays = performance.n
+	if v := performance.value(); v != nil {
+		s.SleepPerformance = *v
+		s.Sleep = sleep / time.Duration(performance.n)
+	}
+
+	sports := map[string]*SportTotal{}
+	for i := range workouts {
+		w := &workouts[i]
+		if !w.Scored() || w.Start == nil {
+			continue
+		}
+		date := analytics.Date(*w.Start, w.TimezoneOffset)
+		if date.Before(start) || !date.Before(end) {
+			continue
+		}
+		sport := w.Sport()
+		t, ok := sports[sport]
+		if !ok {
+			t = &SportTotal{Sport: sport}
+			sports[sport] = t
+		}
+		t.Workouts++
+		t.Strain += w.Score.Strain
+		if w.End != nil {
+			t.Time += w.End.Sub(*w.Start)
+		}
+		s.Workouts++
+	}
+	for _, t := range sports {
+		s.WorkoutTime += t.Time
+		s.SportsByTime = append(s.SportsByTime, *t)
+	}
+	sort.Slice(s.SportsByTime, func(i, j int) bool {
+		a, b := s.SportsByTime[i], s.SportsByTime[j]
+		if a.Time != b.Time {
+			return a.Time > b.Time
+		}
+		return a.Sport < b.Sport
+	})
+	s.SportsByStrain = append([]SportTotal(nil), s.SportsByTime...)
+	sort.SliceStable(s.SportsByStrain, func(i, j int) bool {
+		return s.SportsByStrain[i].Strain > s.SportsByStrain[j].Strain
+	})
+	return s
+}
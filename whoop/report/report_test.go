@@ -0,0 +1,125 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func day(d int) time.Time {
+	return time.Date(2022, 11, d, 0, 0, 0, 0, time.UTC)
+}
+
+// testData returns two weeks of data, from Monday, November 7 2022 to
+// Sunday, November 20, with a recovery of 30 + 5×d on day d, except on
+// day 16, a run on even days and a ride on day 15.
+func testData() Data {
+	var data Data
+	data.Profile = &whoop.UserProfile{ID: 10129, FirstName: whooptest.Ptr("Jane"), LastName: whooptest.Ptr("Doe")}
+	for d := 7; d <= 20; d++ {
+		start := time.Date(2022, 11, d, 7, 0, 0, 0, time.UTC) // 11pm the evening before at -08:00.
+		end := start.Add(24 * time.Hour)
+		c := whoop.Cycle{ID: d, Start: &start, End: &end, TimezoneOffset: whooptest.Ptr("-08:00"), ScoreState: whooptest.Ptr("SCORED")}
+		c.Score.Strain = float64(d)
+		data.Cycles = append(data.Cycles, c)
+
+		if d != 16 {
+			r := whoop.Recovery{CycleID: d, SleepID: d, ScoreState: whooptest.Ptr("SCORED")}
+			r.Score.RecoveryScore = 30 + 5*float64(d-7)
+			data.Recoveries = append(data.Recoveries, r)
+		}
+
+		sleepEnd := start.Add(8 * time.Hour)
+		s := whoop.Sleep{ID: d, Start: &start, End: &sleepEnd, TimezoneOffset: whooptest.Ptr("-08:00"), ScoreState: whooptest.Ptr("SCORED")}
+		s.Score.SleepPerformancePercentage = 90
+		s.Score.StageSummary.TotalLightSleepTimeMilli = int(7 * time.Hour / time.Millisecond)
+		data.Sleeps = append(data.Sleeps, s)
+
+		if d%2 == 0 {
+			ws := time.Date(2022, 11, d+1, 2, 0, 0, 0, time.UTC) // 6pm at -08:00.
+			we := ws.Add(time.Hour)
+			w := whoop.Workout{Start: &ws, End: &we, TimezoneOffset: whooptest.Ptr("-08:00"), SportName: whooptest.Ptr("Running"), ScoreState: whooptest.Ptr("SCORED")}
+			w.Score.Strain = 10
+			data.Workouts = append(data.Workouts, w)
+		}
+	}
+	ws := time.Date(2022, 11, 15, 17, 0, 0, 0, time.UTC)
+	we := ws.Add(2 * time.Hour)
+	w := whoop.Workout{Start: &ws, End: &we, TimezoneOffset: whooptest.Ptr("-08:00"), SportName: whooptest.Ptr("Cycling"), ScoreState: whooptest.Ptr("SCORED")}
+	w.Score.Strain = 8
+	data.Workouts = append(data.Workouts, w)
+	return data
+}
+
+func TestNew(t *testing.T) {
+	r := New(testData(), Week, day(17))
+
+	if r.Name != "Jane Doe" || !r.Start.Equal(day(14)) || !r.End.Equal(day(21)) {
+		t.Errorf("New(): got %q from %v to %v, want %q from %v to %v", r.Name, r.Start, r.End, "Jane Doe", day(14), day(21))
+	}
+	if len(r.Days) != 7 {
+		t.Fatalf("New(): got %d days, want 7", len(r.Days))
+	}
+	if r.Days[2].Recovery != nil || *r.Days[0].Recovery != 65 {
+		t.Errorf("New(): got days %+v, want no recovery on day 16 and 65 on day 14", r.Days)
+	}
+
+	s := r.Summary
+	if s.RecoveryDays != 6 || s.Recovery != (65+70+80+85+90+95)/6.0 {
+		t.Errorf("New(): got recovery %v over %d days, want %v over 6", s.Recovery, s.RecoveryDays, (65+70+80+85+90+95)/6.0)
+	}
+	if s.Green != 5 || s.Yellow != 1 || s.Red != 0 {
+		t.Errorf("New(): got bands %d/%d/%d, want 5/1/0", s.Green, s.Yellow, s.Red)
+	}
+	if s.Strain != 14+15+16+17+18+19+20 || s.StrainDays != 7 || s.AverageStrain != 17 {
+		t.Errorf("New(): got strain %v over %d days, average %v, want 119 over 7, average 17", s.Strain, s.StrainDays, s.AverageStrain)
+	}
+	if s.SleepPerformance != 90 || s.Sleep != 7*time.Hour || s.SleepDays != 7 {
+		t.Errorf("New(): got sleep %v, %v over %d days, want 90, 7h over 7", s.SleepPerformance, s.Sleep, s.SleepDays)
+	}
+	if s.Workouts != 5 || s.WorkoutTime != 6*time.Hour {
+		t.Errorf("New(): got %d workouts for %v, want 5 for 6h", s.Workouts, s.WorkoutTime)
+	}
+	wantByTime := []SportTotal{{"Running", 4, 4 * time.Hour, 40}, {"Cycling", 1, 2 * time.Hour, 8}}
+	if len(s.SportsByTime) != 2 || s.SportsByTime[0] != wantByTime[0] || s.SportsByTime[1] != wantByTime[1] {
+		t.Errorf("New(): got sports by time %+v, want %+v", s.SportsByTime, wantByTime)
+	}
+
+	if r.Previous.RecoveryDays != 7 || r.Previous.Recovery != 45 {
+		t.Errorf("New(): got previous recovery %v over %d days, want 45 over 7", r.Previous.Recovery, r.Previous.RecoveryDays)
+	}
+	if !r.Best.Date.Equal(day(20)) || !r.Worst.Date.Equal(day(14)) {
+		t.Errorf("New(): got best %v and worst %v, want %v and %v", r.Best.Date, r.Worst.Date, day(20), day(14))
+	}
+}
+
+func TestNew_empty(t *testing.T) {
+	r := New(Data{}, Month, day(17))
+	if !r.Start.Equal(day(1)) || !r.End.Equal(time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("New(): got %v to %v, want November 2022", r.Start, r.End)
+	}
+	if len(r.Days) != 30 || r.Best != nil || r.Worst != nil || r.Summary.RecoveryDays != 0 {
+		t.Errorf("New(): got %d days, best %v, worst %v, want 30 empty days", len(r.Days), r.Best, r.Worst)
+	}
+}
+
+func TestPeriod_Start(t *testing.T) {
+	tests := []struct {
+		period Period
+		date   time.Time
+		want   time.Time
+	}{
+		{Week, day(17), day(14)},
+		{Week, day(14), day(14)},
+		{Week, day(20), day(14)},
+		{Month, day(17), day(1)},
+		{Month, time.Date(2022, 11, 17, 23, 0, 0, 0, time.FixedZone("", -8*3600)), day(1)},
+	}
+	for _, tt := range tests {
+		if got := tt.period.Start(tt.date); !got.Equal(tt.want) {
+			t.Errorf("Period(%v).Start(%v): got %v, want %v", tt.period, tt.date, got, tt.want)
+		}
+	}
+}
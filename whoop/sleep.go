@@ -2,6 +2,7 @@ package whoop
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -52,6 +53,48 @@ type Sleep struct {
 		SleepConsistencyPercentage float64 `json:"sleep_consistency_percentage,omitempty"` // Percentage of how similar this sleep and wake times compared to the previous day.
 		SleepEfficiencyPercentage  float64 `json:"sleep_efficiency_percentage,omitempty"`  // Percentage of time user spends in bed that user is actually asleep.
 	} `json:"score,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields returned by the API that are not part of the model, keyed by their dotted path.
+}
+
+// UnmarshalJSON unmarshals data into s, keeping unknown fields in s.Extra.
+func (s *Sleep) UnmarshalJSON(data []byte) error {
+	type sleep Sleep
+	var v sleep
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*s = Sleep(v)
+	s.Extra = extra
+	return nil
+}
+
+// MarshalJSON marshals s, including the unknown fields in s.Extra.
+func (s Sleep) MarshalJSON() ([]byte, error) {
+	type sleep Sleep
+	return marshalWithExtra(sleep(s), s.Extra)
+}
+
+func (s Sleep) unknownFields() []string {
+	return extraKeys("", s.Extra)
+}
+
+// Scored reports whether the sleep has been scored.
+func (s Sleep) Scored() bool {
+	return s.ScoreState != nil && *s.ScoreState == "SCORED"
+}
+
+// TimeAsleep returns the time spent in light, slow wave and REM sleep.
+func (s Sleep) TimeAsleep() time.Duration {
+	st := s.Score.StageSummary
+	ms := st.TotalLightSleepTimeMilli + st.TotalSlowWaveSleepTimeMilli + st.TotalRemSleepTimeMilli
+	return time.Duration(ms) * time.Millisecond
+}
+
+// settled reports whether the sleep has been scored.
+func (s Sleep) settled() bool {
+	return s.Scored()
 }
 
 // GetOne retrieves a single sleep record for the specified id.
@@ -71,6 +114,26 @@ type SleepListAllResp struct {
 	NextToken *string `json:"next_token"`
 }
 
+func (r SleepListAllResp) unknownFields() []string {
+	var fields []string
+	for i, s := range r.Records {
+		fields = append(fields, extraKeys(fmt.Sprintf("records[%v].", i), s.Extra)...)
+	}
+	return fields
+}
+
+func (SleepListAllResp) page() {}
+
+// settled reports whether every record in the page has settled.
+func (r SleepListAllResp) settled() bool {
+	for _, s := range r.Records {
+		if !s.settled() {
+			return false
+		}
+	}
+	return len(r.Records) > 0
+}
+
 // ListAll lists all sleep records for the authenticated user.
 // Results are paginated and sorted by start time in descending order.
 //
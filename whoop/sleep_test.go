@@ -9,6 +9,19 @@ import (
 	"time"
 )
 
+func TestSleep_TimeAsleep(t *testing.T) {
+	var s Sleep
+	s.Score.StageSummary.TotalInBedTimeMilli = 9 * 3600000
+	s.Score.StageSummary.TotalAwakeTimeMilli = 3600000
+	s.Score.StageSummary.TotalLightSleepTimeMilli = 4 * 3600000
+	s.Score.StageSummary.TotalSlowWaveSleepTimeMilli = 2 * 3600000
+	s.Score.StageSummary.TotalRemSleepTimeMilli = 2*3600000 + 30*60000
+
+	if got, want := s.TimeAsleep(), 8*time.Hour+30*time.Minute; got != want {
+		t.Errorf("Sleep.TimeAsleep(): got %v, want %v", got, want)
+	}
+}
+
 func TestSleepService_ListAll(t *testing.T) {
 	client, mux, _, teardown := setup()
 	defer teardown()
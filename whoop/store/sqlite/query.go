@@ -0,0 +1,237 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+// ErrNotFound is returned when a requested record is not in the store.
+var ErrNotFound = errors.New("sqlite: record not found")
+
+// User returns the profile of the user with the given id.
+func (s *Store) User(ctx context.Context, id int) (*whoop.UserProfile, error) {
+	var u whoop.UserProfile
+	var email, first, last sql.NullString
+	err := s.db.QueryRowContext(ctx, `SELECT id, email, first_name, last_name FROM users WHERE id = ?`, id).
+		Scan(&u.ID, &email, &first, &last)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	u.Email, u.FirstName, u.LastName = stringPtr(email), stringPtr(first), stringPtr(last)
+	return &u, nil
+}
+
+// BodyMeasurement returns the body measurements of the user with the given id.
+func (s *Store) BodyMeasurement(ctx context.Context, userID int) (*whoop.BodyMeasurement, error) {
+	var m whoop.BodyMeasurement
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(height_meter, 0), COALESCE(weight_kilogram, 0), COALESCE(max_heart_rate, 0)
+		FROM body_measurements WHERE user_id = ?`, userID).
+		Scan(&m.HeightMeter, &m.WeightKilogram, &m.MaxHeartRate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Cycles returns the cycles of a user starting within [start, end),
+// sorted by start time. A zero start or end leaves that bound open.
+func (s *Store) Cycles(ctx context.Context, userID int, start, end time.Time) ([]whoop.Cycle, error) {
+	where, args := dateRange("start", userID, start, end)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, created_at, updated_at, start, end, timezone_offset, score_state,
+			COALESCE(strain, 0), COALESCE(kilojoule, 0), COALESCE(average_heart_rate, 0), COALESCE(max_heart_rate, 0)
+		FROM cycles WHERE `+where+` ORDER BY start`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var cycles []whoop.Cycle
+	for rows.Next() {
+		var c whoop.Cycle
+		var t times
+		var tz, state sql.NullString
+		if err := rows.Scan(&c.ID, &c.UserID, &t.created, &t.updated, &t.start, &t.end, &tz, &state,
+			&c.Score.Strain, &c.Score.Kilojoule, &c.Score.AverageHeartRate, &c.Score.MaxHeartRate); err != nil {
+			return nil, err
+		}
+		if err := t.into(&c.CreatedAt, &c.UpdatedAt, &c.Start, &c.End); err != nil {
+			return nil, err
+		}
+		c.TimezoneOffset, c.ScoreState = stringPtr(tz), stringPtr(state)
+		cycles = append(cycles, c)
+	}
+	return cycles, rows.Err()
+}
+
+// Recoveries returns the recoveries of a user created within [start, end),
+// sorted by creation time. A zero start or end leaves that bound open.
+func (s *Store) Recoveries(ctx context.Context, userID int, start, end time.Time) ([]whoop.Recovery, error) {
+	where, args := dateRange("created_at", userID, start, end)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT cycle_id, COALESCE(sleep_id, 0), user_id, created_at, updated_at, score_state,
+			COALESCE(user_calibrating, 0), COALESCE(recovery_score, 0), COALESCE(resting_heart_rate, 0),
+			COALESCE(hrv_rmssd_milli, 0), COALESCE(spo2_percentage, 0), COALESCE(skin_temp_celsius, 0)
+		FROM recoveries WHERE `+where+` ORDER BY created_at`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recoveries []whoop.Recovery
+	for rows.Next() {
+		var r whoop.Recovery
+		var t times
+		var state sql.NullString
+		if err := rows.Scan(&r.CycleID, &r.SleepID, &r.UserID, &t.created, &t.updated, &state,
+			&r.Score.UserCalibrating, &r.Score.RecoveryScore, &r.Score.RestingHeartRate,
+			&r.Score.HrvRmssdMilli, &r.Score.Spo2Percentage, &r.Score.SkinTempCelsius); err != nil {
+			return nil, err
+		}
+		if err := t.into(&r.CreatedAt, &r.UpdatedAt, nil, nil); err != nil {
+			return nil, err
+		}
+		r.ScoreState = stringPtr(state)
+		recoveries = append(recoveries, r)
+	}
+	return recoveries, rows.Err()
+}
+
+// Sleeps returns the sleeps of a user starting within [start, end),
+// sorted by start time. A zero start or end leaves that bound open.
+func (s *Store) Sleeps(ctx context.Context, userID int, start, end time.Time) ([]whoop.Sleep, error) {
+	where, args := dateRange("start", userID, start, end)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, created_at, updated_at, start, end, timezone_offset, nap, score_state,
+			COALESCE(total_in_bed_time_milli, 0), COALESCE(total_awake_time_milli, 0),
+			COALESCE(total_no_data_time_milli, 0), COALESCE(total_light_sleep_time_milli, 0),
+			COALESCE(total_slow_wave_sleep_time_milli, 0), COALESCE(total_rem_sleep_time_milli, 0),
+			COALESCE(sleep_cycle_count, 0), COALESCE(disturbance_count, 0),
+			COALESCE(baseline_milli, 0), COALESCE(need_from_sleep_debt_milli, 0),
+			COALESCE(need_from_recent_strain_milli, 0), COALESCE(need_from_recent_nap_milli, 0),
+			COALESCE(respiratory_rate, 0), COALESCE(sleep_performance_percentage, 0),
+			COALESCE(sleep_consistency_percentage, 0), COALESCE(sleep_efficiency_percentage, 0)
+		FROM sleeps WHERE `+where+` ORDER BY start`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sleeps []whoop.Sleep
+	for rows.Next() {
+		var sl whoop.Sleep
+		var t times
+		var tz, state sql.NullString
+		st, need := &sl.Score.StageSummary, &sl.Score.SleepNeeded
+		if err := rows.Scan(&sl.ID, &sl.UserID, &t.created, &t.updated, &t.start, &t.end, &tz, &sl.Nap, &state,
+			&st.TotalInBedTimeMilli, &st.TotalAwakeTimeMilli, &st.TotalNoDataTimeMilli, &st.TotalLightSleepTimeMilli,
+			&st.TotalSlowWaveSleepTimeMilli, &st.TotalRemSleepTimeMilli, &st.SleepCycleCount, &st.DisturbanceCount,
+			&need.BaselineMilli, &need.NeedFromSleepDebtMilli, &need.NeedFromRecentStrainMilli, &need.NeedFromRecentNapMilli,
+			&sl.Score.RespiratoryRate, &sl.Score.SleepPerformancePercentage,
+			&sl.Score.SleepConsistencyPercentage, &sl.Score.SleepEfficiencyPercentage); err != nil {
+			return nil, err
+		}
+		if err := t.into(&sl.CreatedAt, &sl.UpdatedAt, &sl.Start, &sl.End); err != nil {
+			return nil, err
+		}
+		sl.TimezoneOffset, sl.ScoreState = stringPtr(tz), stringPtr(state)
+		sleeps = append(sleeps, sl)
+	}
+	return sleeps, rows.Err()
+}
+
+// Workouts returns the workouts of a user starting within [start, end),
+// sorted by start time. A zero start or end leaves that bound open.
+func (s *Store) Workouts(ctx context.Context, userID int, start, end time.Time) ([]whoop.Workout, error) {
+	where, args := dateRange("start", userID, start, end)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, created_at, updated_at, start, end, timezone_offset, COALESCE(sport_id, 0), sport_name, score_state,
+			COALESCE(strain, 0), COALESCE(average_heart_rate, 0), COALESCE(max_heart_rate, 0), COALESCE(kilojoule, 0),
+			COALESCE(percent_recorded, 0), COALESCE(distance_meter, 0), COALESCE(altitude_gain_meter, 0),
+			COALESCE(altitude_change_meter, 0), COALESCE(zone_zero_milli, 0), COALESCE(zone_one_milli, 0),
+			COALESCE(zone_two_milli, 0), COALESCE(zone_three_milli, 0), COALESCE(zone_four_milli, 0), COALESCE(zone_five_milli, 0)
+		FROM workouts WHERE `+where+` ORDER BY start`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workouts []whoop.Workout
+	for rows.Next() {
+		var w whoop.Workout
+		var t times
+		var tz, sport, state sql.NullString
+		z := &w.Score.ZoneDuration
+		if err := rows.Scan(&w.ID, &w.UserID, &t.created, &t.updated, &t.start, &t.end, &tz, &w.SportID, &sport, &state,
+			&w.Score.Strain, &w.Score.AverageHeartRate, &w.Score.MaxHeartRate, &w.Score.Kilojoule,
+			&w.Score.PercentRecorded, &w.Score.DistanceMeter, &w.Score.AltitudeGainMeter,
+			&w.Score.AltitudeChangeMeter, &z.ZoneZeroMilli, &z.ZoneOneMilli,
+			&z.ZoneTwoMilli, &z.ZoneThreeMilli, &z.ZoneFourMilli, &z.ZoneFiveMilli); err != nil {
+			return nil, err
+		}
+		if err := t.into(&w.CreatedAt, &w.UpdatedAt, &w.Start, &w.End); err != nil {
+			return nil, err
+		}
+		w.TimezoneOffset, w.SportName, w.ScoreState = stringPtr(tz), stringPtr(sport), stringPtr(state)
+		workouts = append(workouts, w)
+	}
+	return workouts, rows.Err()
+}
+
+// dateRange returns the WHERE clause and arguments selecting the rows of
+// userID whose column is within [start, end).
+func dateRange(column string, userID int, start, end time.Time) (string, []any) {
+	conds := []string{"user_id = ?"}
+	args := []any{userID}
+	if !start.IsZero() {
+		conds = append(conds, column+" >= ?")
+		args = append(args, timeArg(&start))
+	}
+	if !end.IsZero() {
+		conds = append(conds, column+" < ?")
+		args = append(args, timeArg(&end))
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+// times holds the nullable time columns of a row.
+type times struct {
+	created, updated, start, end sql.NullString
+}
+
+// into parses the scanned times into the given fields. Nil fields are skipped.
+func (t times) into(created, updated, start, end **time.Time) error {
+	for _, f := range []struct {
+		col sql.NullString
+		dst **time.Time
+	}{{t.created, created}, {t.updated, updated}, {t.start, start}, {t.end, end}} {
+		if f.dst == nil || !f.col.Valid {
+			continue
+		}
+		v, err := time.Parse(timeFormat, f.col.String)
+		if err != nil {
+			return err
+		}
+		*f.dst = &v
+	}
+	return nil
+}
+
+func stringPtr(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
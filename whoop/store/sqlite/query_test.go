@@ -0,0 +1,57 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestStore_Cycles_dateRange(t *testing.T) {
+	s := setup(t)
+	ctx := context.Background()
+	day := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 5; i++ {
+		start := day.AddDate(0, 0, i)
+		c := whoop.Cycle{ID: i + 1, UserID: 10, Start: &start, ScoreState: whooptest.Ptr("PENDING_SCORE")}
+		c.Score.Strain = 10
+		s.UpsertCycle(ctx, &c)
+	}
+	s.UpsertCycle(ctx, &whoop.Cycle{ID: 99, UserID: 11, Start: &day})
+
+	testCases := []struct {
+		start, end time.Time
+		want       []int
+	}{
+		{time.Time{}, time.Time{}, []int{1, 2, 3, 4, 5}},
+		{day.AddDate(0, 0, 1), day.AddDate(0, 0, 3), []int{2, 3}},
+		{day.AddDate(0, 0, 3), time.Time{}, []int{4, 5}},
+		{time.Time{}, day.Add(time.Nanosecond), []int{1}},
+	}
+	for _, test := range testCases {
+		got, err := s.Cycles(ctx, 10, test.start, test.end)
+		if err != nil {
+			t.Fatalf("Store.Cycles(): expected nil error, got %v", err)
+		}
+		var ids []int
+		for _, c := range got {
+			ids = append(ids, c.ID)
+			if c.Score.Strain != 0 {
+				t.Errorf("Store.Cycles(): expected unscored cycle to have no strain, got %v", c.Score.Strain)
+			}
+		}
+		if len(ids) != len(test.want) {
+			t.Errorf("Store.Cycles(%v, %v): got %v, want %v", test.start, test.end, ids, test.want)
+			continue
+		}
+		for i := range ids {
+			if ids[i] != test.want[i] {
+				t.Errorf("Store.Cycles(%v, %v): got %v, want %v", test.start, test.end, ids, test.want)
+				break
+			}
+		}
+	}
+}
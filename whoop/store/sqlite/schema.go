@@ -0,0 +1,109 @@
+package sqlite
+
+// schema is the normalized schema of the store. Times are stored as
+// RFC 3339 text in UTC so that they sort chronologically, and score
+// columns are NULL for records that have not been scored.
+const schema = `
+CREATE TABLE IF NOT EXISTS users (
+	id         INTEGER PRIMARY KEY,
+	email      TEXT,
+	first_name TEXT,
+	last_name  TEXT
+);
+
+CREATE TABLE IF NOT EXISTS body_measurements (
+	user_id         INTEGER PRIMARY KEY,
+	height_meter    REAL,
+	weight_kilogram REAL,
+	max_heart_rate  INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS cycles (
+	id                 INTEGER PRIMARY KEY,
+	user_id            INTEGER NOT NULL,
+	created_at         TEXT,
+	updated_at         TEXT,
+	start              TEXT,
+	end                TEXT,
+	timezone_offset    TEXT,
+	score_state        TEXT,
+	strain             REAL,
+	kilojoule          REAL,
+	average_heart_rate REAL,
+	max_heart_rate     REAL
+);
+CREATE INDEX IF NOT EXISTS cycles_user_start ON cycles (user_id, start);
+
+CREATE TABLE IF NOT EXISTS recoveries (
+	cycle_id           INTEGER PRIMARY KEY,
+	sleep_id           INTEGER,
+	user_id            INTEGER NOT NULL,
+	created_at         TEXT,
+	updated_at         TEXT,
+	score_state        TEXT,
+	user_calibrating   INTEGER,
+	recovery_score     REAL,
+	resting_heart_rate REAL,
+	hrv_rmssd_milli    REAL,
+	spo2_percentage    REAL,
+	skin_temp_celsius  REAL
+);
+CREATE INDEX IF NOT EXISTS recoveries_user_created ON recoveries (user_id, created_at);
+
+CREATE TABLE IF NOT EXISTS sleeps (
+	id                               INTEGER PRIMARY KEY,
+	user_id                          INTEGER NOT NULL,
+	created_at                       TEXT,
+	updated_at                       TEXT,
+	start                            TEXT,
+	end                              TEXT,
+	timezone_offset                  TEXT,
+	nap                              INTEGER NOT NULL DEFAULT 0,
+	score_state                      TEXT,
+	total_in_bed_time_milli          INTEGER,
+	total_awake_time_milli           INTEGER,
+	total_no_data_time_milli         INTEGER,
+	total_light_sleep_time_milli     INTEGER,
+	total_slow_wave_sleep_time_milli INTEGER,
+	total_rem_sleep_time_milli       INTEGER,
+	sleep_cycle_count                INTEGER,
+	disturbance_count                INTEGER,
+	baseline_milli                   INTEGER,
+	need_from_sleep_debt_milli       INTEGER,
+	need_from_recent_strain_milli    INTEGER,
+	need_from_recent_nap_milli       INTEGER,
+	respiratory_rate                 REAL,
+	sleep_performance_percentage     REAL,
+	sleep_consistency_percentage     REAL,
+	sleep_efficiency_percentage      REAL
+);
+CREATE INDEX IF NOT EXISTS sleeps_user_start ON sleeps (user_id, start);
+
+CREATE TABLE IF NOT EXISTS workouts (
+	id                    INTEGER PRIMARY KEY,
+	user_id               INTEGER NOT NULL,
+	created_at            TEXT,
+	updated_at            TEXT,
+	start                 TEXT,
+	end                   TEXT,
+	timezone_offset       TEXT,
+	sport_id              INTEGER,
+	sport_name            TEXT,
+	score_state           TEXT,
+	strain                REAL,
+	average_heart_rate    INTEGER,
+	max_heart_rate        INTEGER,
+	kilojoule             REAL,
+	percent_recorded      REAL,
+	distance_meter        REAL,
+	altitude_gain_meter   REAL,
+	altitude_change_meter REAL,
+	zone_zero_milli       INTEGER,
+	zone_one_milli        INTEGER,
+	zone_two_milli        INTEGER,
+	zone_three_milli      INTEGER,
+	zone_four_milli       INTEGER,
+	zone_five_milli       INTEGER
+);
+CREATE INDEX IF NOT EXISTS workouts_user_start ON workouts (user_id, start);
+`
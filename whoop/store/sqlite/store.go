@@ -0,0 +1,216 @@
+// Package sqlite stores WHOOP data in a SQLite database.
+//
+// The Store defines a normalized schema for users, body measurements,
+// cycles, recoveries, sleeps and workouts. Records are upserted by ID and
+// only replaced by versions with the same or a later UpdatedAt, so the
+// Store can be used as the sink of a syncer.Engine.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/syncer"
+
+	_ "modernc.org/sqlite" // Registers the "sqlite" database/sql driver.
+)
+
+// timeFormat is a fixed width RFC 3339 layout, so that stored times sort
+// chronologically.
+const timeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Store is a SQLite backed store of WHOOP data.
+// It is safe for concurrent use.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the SQLite database at path, creating it and its schema if needed.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite allows a single writer at a time.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlite: creating schema: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// DB returns the underlying database, for queries not covered by the Store.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+// Apply stores the record of a sync change. It implements syncer.Sink.
+func (s *Store) Apply(ctx context.Context, change syncer.Change) error {
+	switch r := change.Record.Data.(type) {
+	case *whoop.Cycle:
+		return s.UpsertCycle(ctx, r)
+	case *whoop.Recovery:
+		return s.UpsertRecovery(ctx, r)
+	case *whoop.Sleep:
+		return s.UpsertSleep(ctx, r)
+	case *whoop.Workout:
+		return s.UpsertWorkout(ctx, r)
+	}
+	return fmt.Errorf("sqlite: unsupported record type %T", change.Record.Data)
+}
+
+// UpsertUser inserts or replaces a user profile.
+func (s *Store) UpsertUser(ctx context.Context, u *whoop.UserProfile) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO users (id, email, first_name, last_name) VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			email = excluded.email, first_name = excluded.first_name, last_name = excluded.last_name`,
+		u.ID, u.Email, u.FirstName, u.LastName)
+	return err
+}
+
+// UpsertBodyMeasurement inserts or replaces the body measurements of a user.
+func (s *Store) UpsertBodyMeasurement(ctx context.Context, userID int, m *whoop.BodyMeasurement) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO body_measurements (user_id, height_meter, weight_kilogram, max_heart_rate) VALUES (?, ?, ?, ?)
+		ON CONFLICT (user_id) DO UPDATE SET
+			height_meter = excluded.height_meter, weight_kilogram = excluded.weight_kilogram, max_heart_rate = excluded.max_heart_rate`,
+		userID, m.HeightMeter, m.WeightKilogram, m.MaxHeartRate)
+	return err
+}
+
+// UpsertCycle inserts a cycle, or replaces it if c is not older than the stored version.
+func (s *Store) UpsertCycle(ctx context.Context, c *whoop.Cycle) error {
+	sc := c.Scored()
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO cycles (id, user_id, created_at, updated_at, start, end, timezone_offset, score_state,
+			strain, kilojoule, average_heart_rate, max_heart_rate)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = excluded.user_id, created_at = excluded.created_at, updated_at = excluded.updated_at,
+			start = excluded.start, end = excluded.end, timezone_offset = excluded.timezone_offset,
+			score_state = excluded.score_state, strain = excluded.strain, kilojoule = excluded.kilojoule,
+			average_heart_rate = excluded.average_heart_rate, max_heart_rate = excluded.max_heart_rate
+		WHERE `+notOlder("cycles"),
+		c.ID, c.UserID, timeArg(c.CreatedAt), timeArg(c.UpdatedAt), timeArg(c.Start), timeArg(c.End), c.TimezoneOffset, c.ScoreState,
+		score(sc, c.Score.Strain), score(sc, c.Score.Kilojoule), score(sc, c.Score.AverageHeartRate), score(sc, c.Score.MaxHeartRate))
+	return err
+}
+
+// UpsertRecovery inserts a recovery, or replaces it if r is not older than the stored version.
+func (s *Store) UpsertRecovery(ctx context.Context, r *whoop.Recovery) error {
+	sc := r.Scored()
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO recoveries (cycle_id, sleep_id, user_id, created_at, updated_at, score_state,
+			user_calibrating, recovery_score, resting_heart_rate, hrv_rmssd_milli, spo2_percentage, skin_temp_celsius)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (cycle_id) DO UPDATE SET
+			sleep_id = excluded.sleep_id, user_id = excluded.user_id, created_at = excluded.created_at,
+			updated_at = excluded.updated_at, score_state = excluded.score_state,
+			user_calibrating = excluded.user_calibrating, recovery_score = excluded.recovery_score,
+			resting_heart_rate = excluded.resting_heart_rate, hrv_rmssd_milli = excluded.hrv_rmssd_milli,
+			spo2_percentage = excluded.spo2_percentage, skin_temp_celsius = excluded.skin_temp_celsius
+		WHERE `+notOlder("recoveries"),
+		r.CycleID, r.SleepID, r.UserID, timeArg(r.CreatedAt), timeArg(r.UpdatedAt), r.ScoreState,
+		score(sc, r.Score.UserCalibrating), score(sc, r.Score.RecoveryScore), score(sc, r.Score.RestingHeartRate),
+		score(sc, r.Score.HrvRmssdMilli), score(sc, r.Score.Spo2Percentage), score(sc, r.Score.SkinTempCelsius))
+	return err
+}
+
+// UpsertSleep inserts a sleep, or replaces it if sl is not older than the stored version.
+func (s *Store) UpsertSleep(ctx context.Context, sl *whoop.Sleep) error {
+	sc := sl.Scored()
+	st, need := sl.Score.StageSummary, sl.Score.SleepNeeded
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO sleeps (id, user_id, created_at, updated_at, start, end, timezone_offset, nap, score_state,
+			total_in_bed_time_milli, total_awake_time_milli, total_no_data_time_milli, total_light_sleep_time_milli,
+			total_slow_wave_sleep_time_milli, total_rem_sleep_time_milli, sleep_cycle_count, disturbance_count,
+			baseline_milli, need_from_sleep_debt_milli, need_from_recent_strain_milli, need_from_recent_nap_milli,
+			respiratory_rate, sleep_performance_percentage, sleep_consistency_percentage, sleep_efficiency_percentage)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = excluded.user_id, created_at = excluded.created_at, updated_at = excluded.updated_at,
+			start = excluded.start, end = excluded.end, timezone_offset = excluded.timezone_offset,
+			nap = excluded.nap, score_state = excluded.score_state,
+			total_in_bed_time_milli = excluded.total_in_bed_time_milli,
+			total_awake_time_milli = excluded.total_awake_time_milli,
+			total_no_data_time_milli = excluded.total_no_data_time_milli,
+			total_light_sleep_time_milli = excluded.total_light_sleep_time_milli,
+			total_slow_wave_sleep_time_milli = excluded.total_slow_wave_sleep_time_milli,
+			total_rem_sleep_time_milli = excluded.total_rem_sleep_time_milli,
+			sleep_cycle_count = excluded.sleep_cycle_count, disturbance_count = excluded.disturbance_count,
+			baseline_milli = excluded.baseline_milli, need_from_sleep_debt_milli = excluded.need_from_sleep_debt_milli,
+			need_from_recent_strain_milli = excluded.need_from_recent_strain_milli,
+			need_from_recent_nap_milli = excluded.need_from_recent_nap_milli,
+			respiratory_rate = excluded.respiratory_rate,
+			sleep_performance_percentage = excluded.sleep_performance_percentage,
+			sleep_consistency_percentage = excluded.sleep_consistency_percentage,
+			sleep_efficiency_percentage = excluded.sleep_efficiency_percentage
+		WHERE `+notOlder("sleeps"),
+		sl.ID, sl.UserID, timeArg(sl.CreatedAt), timeArg(sl.UpdatedAt), timeArg(sl.Start), timeArg(sl.End), sl.TimezoneOffset, sl.Nap, sl.ScoreState,
+		score(sc, st.TotalInBedTimeMilli), score(sc, st.TotalAwakeTimeMilli), score(sc, st.TotalNoDataTimeMilli), score(sc, st.TotalLightSleepTimeMilli),
+		score(sc, st.TotalSlowWaveSleepTimeMilli), score(sc, st.TotalRemSleepTimeMilli), score(sc, st.SleepCycleCount), score(sc, st.DisturbanceCount),
+		score(sc, need.BaselineMilli), score(sc, need.NeedFromSleepDebtMilli), score(sc, need.NeedFromRecentStrainMilli), score(sc, need.NeedFromRecentNapMilli),
+		score(sc, sl.Score.RespiratoryRate), score(sc, sl.Score.SleepPerformancePercentage),
+		score(sc, sl.Score.SleepConsistencyPercentage), score(sc, sl.Score.SleepEfficiencyPercentage))
+	return err
+}
+
+// UpsertWorkout inserts a workout, or replaces it if w is not older than the stored version.
+func (s *Store) UpsertWorkout(ctx context.Context, w *whoop.Workout) error {
+	sc := w.Scored()
+	z := w.Score.ZoneDuration
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO workouts (id, user_id, created_at, updated_at, start, end, timezone_offset, sport_id, sport_name, score_state,
+			strain, average_heart_rate, max_heart_rate, kilojoule, percent_recorded, distance_meter, altitude_gain_meter,
+			altitude_change_meter, zone_zero_milli, zone_one_milli, zone_two_milli, zone_three_milli, zone_four_milli, zone_five_milli)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = excluded.user_id, created_at = excluded.created_at, updated_at = excluded.updated_at,
+			start = excluded.start, end = excluded.end, timezone_offset = excluded.timezone_offset,
+			sport_id = excluded.sport_id, sport_name = excluded.sport_name, score_state = excluded.score_state,
+			strain = excluded.strain, average_heart_rate = excluded.average_heart_rate,
+			max_heart_rate = excluded.max_heart_rate, kilojoule = excluded.kilojoule,
+			percent_recorded = excluded.percent_recorded, distance_meter = excluded.distance_meter,
+			altitude_gain_meter = excluded.altitude_gain_meter, altitude_change_meter = excluded.altitude_change_meter,
+			zone_zero_milli = excluded.zone_zero_milli, zone_one_milli = excluded.zone_one_milli,
+			zone_two_milli = excluded.zone_two_milli, zone_three_milli = excluded.zone_three_milli,
+			zone_four_milli = excluded.zone_four_milli, zone_five_milli = excluded.zone_five_milli
+		WHERE `+notOlder("workouts"),
+		w.ID, w.UserID, timeArg(w.CreatedAt), timeArg(w.UpdatedAt), timeArg(w.Start), timeArg(w.End), w.TimezoneOffset, w.SportID, w.SportName, w.ScoreState,
+		score(sc, w.Score.Strain), score(sc, w.Score.AverageHeartRate), score(sc, w.Score.MaxHeartRate), score(sc, w.Score.Kilojoule),
+		score(sc, w.Score.PercentRecorded), score(sc, w.Score.DistanceMeter), score(sc, w.Score.AltitudeGainMeter), score(sc, w.Score.AltitudeChangeMeter),
+		score(sc, z.ZoneZeroMilli), score(sc, z.ZoneOneMilli), score(sc, z.ZoneTwoMilli), score(sc, z.ZoneThreeMilli), score(sc, z.ZoneFourMilli), score(sc, z.ZoneFiveMilli))
+	return err
+}
+
+// notOlder returns the upsert condition that keeps the stored row of table
+// if it has a later UpdatedAt than the inserted one.
+func notOlder(table string) string {
+	return fmt.Sprintf("%[1]v.updated_at IS NULL OR excluded.updated_at IS NULL OR excluded.updated_at >= %[1]v.updated_at", table)
+}
+
+// score returns v, or nil so that the column is NULL if the record is not scored.
+func score(scored bool, v any) any {
+	if !scored {
+		return nil
+	}
+	return v
+}
+
+// timeArg formats t for storage, or returns nil if t is nil.
+func timeArg(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC().Format(timeFormat)
+}
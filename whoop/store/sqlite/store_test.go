@@ -0,0 +1,122 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/syncer"
+)
+
+func setup(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "whoop.db"))
+	if err != nil {
+		t.Fatalf("Open(): expected nil error, got %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestStore_UpsertCycle(t *testing.T) {
+	s := setup(t)
+	ctx := context.Background()
+	start := time.Date(2022, 11, 27, 8, 17, 45, 687000000, time.UTC)
+
+	c := whoop.Cycle{ID: 1, UserID: 10, Start: &start, UpdatedAt: whooptest.Ptr(start.Add(time.Hour)), ScoreState: whooptest.Ptr("SCORED"), TimezoneOffset: whooptest.Ptr("-08:00")}
+	c.Score.Strain = 12.5
+	if err := s.UpsertCycle(ctx, &c); err != nil {
+		t.Fatalf("Store.UpsertCycle(): expected nil error, got %v", err)
+	}
+
+	// An older version must not replace the stored one.
+	old := c
+	old.UpdatedAt = &start
+	old.Score.Strain = 3
+	if err := s.UpsertCycle(ctx, &old); err != nil {
+		t.Fatalf("Store.UpsertCycle(): expected nil error, got %v", err)
+	}
+	got, err := s.Cycles(ctx, 10, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Store.Cycles(): expected nil error, got %v", err)
+	}
+	if len(got) != 1 || got[0].Score.Strain != 12.5 {
+		t.Fatalf("Store.Cycles(): expected cycle with strain 12.5, got %+v", got)
+	}
+	if !got[0].Start.Equal(start) || *got[0].TimezoneOffset != "-08:00" || got[0].End != nil {
+		t.Errorf("Store.Cycles(): got %+v, want %+v", got[0], c)
+	}
+
+	// A newer version replaces it.
+	c.UpdatedAt = whooptest.Ptr(start.Add(2 * time.Hour))
+	c.Score.Strain = 14
+	s.UpsertCycle(ctx, &c)
+	got, _ = s.Cycles(ctx, 10, time.Time{}, time.Time{})
+	if len(got) != 1 || got[0].Score.Strain != 14 {
+		t.Errorf("Store.Cycles(): expected cycle with strain 14, got %+v", got)
+	}
+}
+
+func TestStore_Apply(t *testing.T) {
+	s := setup(t)
+	ctx := context.Background()
+	start := time.Date(2022, 11, 27, 0, 0, 0, 0, time.UTC)
+
+	sl := &whoop.Sleep{ID: 1, UserID: 10, Start: &start, Nap: true, ScoreState: whooptest.Ptr("SCORED")}
+	sl.Score.StageSummary.TotalRemSleepTimeMilli = 5400000
+	sl.Score.SleepNeeded.NeedFromSleepDebtMilli = 600000
+	w := &whoop.Workout{ID: 2, UserID: 10, Start: &start, SportID: 1, SportName: whooptest.Ptr("Cycling"), ScoreState: whooptest.Ptr("SCORED")}
+	w.Score.ZoneDuration.ZoneFourMilli = 900000
+	changes := []syncer.Change{
+		{Record: syncer.Record{Data: &whoop.Cycle{ID: 1, UserID: 10, Start: &start}}},
+		{Record: syncer.Record{Data: &whoop.Recovery{CycleID: 1, SleepID: 1, UserID: 10, CreatedAt: &start}}},
+		{Record: syncer.Record{Data: sl}},
+		{Record: syncer.Record{Data: w}},
+	}
+	var sink syncer.Sink = s
+	for _, c := range changes {
+		if err := sink.Apply(ctx, c); err != nil {
+			t.Fatalf("Store.Apply(): expected nil error, got %v", err)
+		}
+	}
+	if err := sink.Apply(ctx, syncer.Change{}); err == nil {
+		t.Error("Store.Apply(): expected error for unsupported record, got nil")
+	}
+
+	sleeps, _ := s.Sleeps(ctx, 10, time.Time{}, time.Time{})
+	if len(sleeps) != 1 || !sleeps[0].Nap || sleeps[0].Score.StageSummary.TotalRemSleepTimeMilli != 5400000 || sleeps[0].Score.SleepNeeded.NeedFromSleepDebtMilli != 600000 {
+		t.Errorf("Store.Sleeps(): got %+v, want %+v", sleeps, sl)
+	}
+	workouts, _ := s.Workouts(ctx, 10, time.Time{}, time.Time{})
+	if len(workouts) != 1 || *workouts[0].SportName != "Cycling" || workouts[0].Score.ZoneDuration.ZoneFourMilli != 900000 {
+		t.Errorf("Store.Workouts(): got %+v, want %+v", workouts, w)
+	}
+	recoveries, _ := s.Recoveries(ctx, 10, time.Time{}, time.Time{})
+	if len(recoveries) != 1 || recoveries[0].SleepID != 1 || recoveries[0].ScoreState != nil {
+		t.Errorf("Store.Recoveries(): got %+v", recoveries)
+	}
+}
+
+func TestStore_UserAndBodyMeasurement(t *testing.T) {
+	s := setup(t)
+	ctx := context.Background()
+
+	if _, err := s.User(ctx, 10); err != ErrNotFound {
+		t.Errorf("Store.User(): expected ErrNotFound, got %v", err)
+	}
+	s.UpsertUser(ctx, &whoop.UserProfile{ID: 10, Email: whooptest.Ptr("a@b.c")})
+	s.UpsertUser(ctx, &whoop.UserProfile{ID: 10, Email: whooptest.Ptr("new@b.c"), FirstName: whooptest.Ptr("Ana")})
+	u, err := s.User(ctx, 10)
+	if err != nil || *u.Email != "new@b.c" || *u.FirstName != "Ana" || u.LastName != nil {
+		t.Errorf("Store.User(): got %+v %v", u, err)
+	}
+
+	s.UpsertBodyMeasurement(ctx, 10, &whoop.BodyMeasurement{HeightMeter: 1.8, WeightKilogram: 80, MaxHeartRate: 190})
+	m, err := s.BodyMeasurement(ctx, 10)
+	if err != nil || m.HeightMeter != 1.8 || m.WeightKilogram != 80 || m.MaxHeartRate != 190 {
+		t.Errorf("Store.BodyMeasurement(): got %+v %v", m, err)
+	}
+}
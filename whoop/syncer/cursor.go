@@ -0,0 +1,124 @@
+package syncer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Cursor is the sync progress of a single user and collection.
+type Cursor struct {
+	// SyncedUntil is the end of the last completed sync window.
+	// It is zero if no sync has completed yet.
+	SyncedUntil time.Time `json:"synced_until"`
+
+	// Seen holds the records emitted within the lookback window,
+	// keyed by record ID, used to tell inserts from updates.
+	Seen map[int]Seen `json:"seen,omitempty"`
+}
+
+// Seen records the state of a record when it was last emitted.
+type Seen struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Time      time.Time `json:"time"`
+}
+
+// CursorStore persists sync cursors.
+// Implementations must be safe for concurrent use.
+type CursorStore interface {
+	// Load returns the cursor for userID and c, or a zero Cursor if none has been saved.
+	Load(ctx context.Context, userID string, c Collection) (Cursor, error)
+	// Save stores the cursor for userID and c.
+	Save(ctx context.Context, userID string, c Collection, cursor Cursor) error
+}
+
+// MemoryCursorStore is a CursorStore that keeps cursors in memory.
+// It doesn't survive restarts and is mostly useful for testing.
+type MemoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]Cursor
+}
+
+// NewMemoryCursorStore returns an empty MemoryCursorStore.
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{cursors: make(map[string]Cursor)}
+}
+
+// Load returns the cursor for userID and c.
+func (m *MemoryCursorStore) Load(ctx context.Context, userID string, c Collection) (Cursor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return copyCursor(m.cursors[userID+"/"+string(c)]), nil
+}
+
+// Save stores the cursor for userID and c.
+func (m *MemoryCursorStore) Save(ctx context.Context, userID string, c Collection, cursor Cursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cursors[userID+"/"+string(c)] = copyCursor(cursor)
+	return nil
+}
+
+func copyCursor(c Cursor) Cursor {
+	seen := make(map[int]Seen, len(c.Seen))
+	for k, v := range c.Seen {
+		seen[k] = v
+	}
+	c.Seen = seen
+	return c
+}
+
+// FileCursorStore is a CursorStore that keeps one JSON file per user
+// and collection in a directory.
+type FileCursorStore struct {
+	Dir string // Directory holding the cursor files. It is created if needed.
+}
+
+func (f *FileCursorStore) path(userID string, c Collection) string {
+	return filepath.Join(f.Dir, fmt.Sprintf("%v.%v.json", url.PathEscape(userID), c))
+}
+
+// Load returns the cursor for userID and c.
+func (f *FileCursorStore) Load(ctx context.Context, userID string, c Collection) (Cursor, error) {
+	var cursor Cursor
+	data, err := os.ReadFile(f.path(userID, c))
+	if errors.Is(err, fs.ErrNotExist) {
+		return cursor, nil
+	}
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// Save atomically stores the cursor for userID and c.
+func (f *FileCursorStore) Save(ctx context.Context, userID string, c Collection, cursor Cursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(f.Dir, ".cursor-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path(userID, c))
+}
@@ -0,0 +1,49 @@
+package syncer
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestFileCursorStore(t *testing.T) {
+	store := &FileCursorStore{Dir: t.TempDir()}
+	ctx := context.Background()
+
+	got, err := store.Load(ctx, "user/1", Sleeps)
+	if err != nil {
+		t.Fatalf("FileCursorStore.Load(): expected nil error, got %v", err)
+	}
+	if !got.SyncedUntil.IsZero() || len(got.Seen) != 0 {
+		t.Errorf("FileCursorStore.Load(): expected zero cursor, got %+v", got)
+	}
+
+	date := time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC)
+	want := Cursor{SyncedUntil: date, Seen: map[int]Seen{7: {UpdatedAt: date, Time: date}}}
+	if err := store.Save(ctx, "user/1", Sleeps, want); err != nil {
+		t.Fatalf("FileCursorStore.Save(): expected nil error, got %v", err)
+	}
+	got, err = store.Load(ctx, "user/1", Sleeps)
+	if err != nil {
+		t.Fatalf("FileCursorStore.Load(): expected nil error, got %v", err)
+	}
+	if !got.SyncedUntil.Equal(date) || !got.Seen[7].UpdatedAt.Equal(date) {
+		t.Errorf("FileCursorStore.Load(): got %+v, want %+v", got, want)
+	}
+	if other, _ := store.Load(ctx, "user/1", Cycles); !other.SyncedUntil.IsZero() {
+		t.Errorf("FileCursorStore.Load(): expected cursors to be per collection, got %+v", other)
+	}
+}
+
+func TestMemoryCursorStore(t *testing.T) {
+	store := NewMemoryCursorStore()
+	ctx := context.Background()
+	c := Cursor{Seen: map[int]Seen{1: {}}}
+	store.Save(ctx, "u1", Cycles, c)
+	c.Seen[2] = Seen{}
+
+	got, _ := store.Load(ctx, "u1", Cycles)
+	if len(got.Seen) != 1 {
+		t.Errorf("MemoryCursorStore.Load(): expected stored cursor to be a copy, got %+v", got)
+	}
+}
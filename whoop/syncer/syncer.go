@@ -0,0 +1,264 @@
+// Package syncer keeps a local mirror of WHOOP data up to date.
+//
+// An Engine pages through the ListAll endpoints of a whoop.Client for
+// each collection, remembering per user and per collection the time window
+// that has already been synced. Every sync re-fetches a lookback window so
+// that records which were still PENDING_SCORE when first seen are emitted
+// again once their UpdatedAt changes. Progress is persisted in a CursorStore
+// after every page, so a crashed sync resumes without emitting duplicates.
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+const (
+	defaultLookback = 72 * time.Hour
+	defaultPageSize = 25
+)
+
+// Collection identifies a WHOOP record collection.
+type Collection string
+
+// Collections supported by the sync engine.
+const (
+	Cycles     Collection = "cycle"
+	Recoveries Collection = "recovery"
+	Sleeps     Collection = "sleep"
+	Workouts   Collection = "workout"
+)
+
+// AllCollections lists every collection supported by the sync engine.
+var AllCollections = []Collection{Cycles, Recoveries, Sleeps, Workouts}
+
+// Op is the kind of change emitted to a Sink.
+type Op string
+
+// Operations emitted to a Sink.
+const (
+	Insert Op = "insert" // The record has not been seen before.
+	Update Op = "update" // The record has been seen before and its UpdatedAt changed.
+)
+
+// Record is a single WHOOP record fetched during a sync.
+type Record struct {
+	Collection Collection
+	ID         int       // Record ID. For recoveries, the cycle ID.
+	UpdatedAt  time.Time // Time the record was last updated.
+	Time       time.Time // Start time of the record, or creation time for recoveries.
+
+	// Data holds the record itself: a *whoop.Cycle, *whoop.Recovery,
+	// *whoop.Sleep or *whoop.Workout depending on Collection.
+	Data any
+}
+
+// Change is a record insert or update emitted to a Sink.
+type Change struct {
+	UserID string
+	Op     Op
+	Record Record
+}
+
+// Sink receives the changes found during a sync.
+type Sink interface {
+	Apply(ctx context.Context, change Change) error
+}
+
+// SinkFunc adapts an ordinary function to the Sink interface.
+type SinkFunc func(ctx context.Context, change Change) error
+
+// Apply calls f(ctx, change).
+func (f SinkFunc) Apply(ctx context.Context, change Change) error {
+	return f(ctx, change)
+}
+
+// Engine syncs WHOOP collections into a Sink.
+type Engine struct {
+	Cursors CursorStore // Persists sync progress. Required.
+	Sink    Sink        // Receives inserts and updates. Required.
+
+	// Start is the beginning of the first sync for a user and collection.
+	// If zero, the whole history available from the API is synced.
+	Start time.Time
+
+	// Lookback is how far before the end of the last synced window
+	// records are re-fetched to catch late score updates.
+	// Defaults to 72 hours.
+	Lookback time.Duration
+
+	// PageSize is the number of records requested per page.
+	// Defaults to 25, the maximum allowed by the API.
+	PageSize int
+}
+
+// Sync fetches new and updated records of the given collections for the
+// user authenticated by client, and applies them to the Sink. If no
+// collections are given, AllCollections are synced.
+func (e *Engine) Sync(ctx context.Context, userID string, client *whoop.Client, collections ...Collection) error {
+	if e.Cursors == nil || e.Sink == nil {
+		return errors.New("syncer: Engine requires a CursorStore and a Sink")
+	}
+	if len(collections) == 0 {
+		collections = AllCollections
+	}
+	for _, c := range collections {
+		if err := e.syncCollection(ctx, userID, client, c); err != nil {
+			return fmt.Errorf("syncer: syncing %v for user %v: %w", c, userID, err)
+		}
+	}
+	return nil
+}
+
+func (e *Engine) syncCollection(ctx context.Context, userID string, client *whoop.Client, c Collection) error {
+	fetch, ok := fetchers[c]
+	if !ok {
+		return fmt.Errorf("unknown collection %q", c)
+	}
+	cursor, err := e.Cursors.Load(ctx, userID, c)
+	if err != nil {
+		return err
+	}
+	if cursor.Seen == nil {
+		cursor.Seen = make(map[int]Seen)
+	}
+
+	end := now()
+	params := &whoop.RequestParams{Start: e.Start, End: end, Limit: e.pageSize()}
+	if !cursor.SyncedUntil.IsZero() {
+		params.Start = cursor.SyncedUntil.Add(-e.lookback())
+	}
+
+	for {
+		records, next, err := fetch(ctx, client, params)
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			seen, ok := cursor.Seen[r.ID]
+			op := Insert
+			if ok {
+				if !r.UpdatedAt.After(seen.UpdatedAt) {
+					continue
+				}
+				op = Update
+			}
+			if err := e.Sink.Apply(ctx, Change{UserID: userID, Op: op, Record: r}); err != nil {
+				return err
+			}
+			cursor.Seen[r.ID] = Seen{UpdatedAt: r.UpdatedAt, Time: r.Time}
+		}
+		if next == "" {
+			break
+		}
+		// Persist the records seen so far, so that a crash doesn't
+		// cause them to be emitted again when the sync is resumed.
+		if err := e.Cursors.Save(ctx, userID, c, cursor); err != nil {
+			return err
+		}
+		params.NextToken = next
+	}
+
+	// Forget records well outside the next lookback window. They are kept
+	// for twice the lookback since the API may return records overlapping
+	// the start of the window.
+	cursor.SyncedUntil = end
+	cutoff := end.Add(-2 * e.lookback())
+	for id, s := range cursor.Seen {
+		if s.Time.Before(cutoff) {
+			delete(cursor.Seen, id)
+		}
+	}
+	return e.Cursors.Save(ctx, userID, c, cursor)
+}
+
+func (e *Engine) lookback() time.Duration {
+	if e.Lookback <= 0 {
+		return defaultLookback
+	}
+	return e.Lookback
+}
+
+func (e *Engine) pageSize() int {
+	if e.PageSize <= 0 {
+		return defaultPageSize
+	}
+	return e.PageSize
+}
+
+// fetchFunc fetches a page of records of a collection.
+type fetchFunc func(ctx context.Context, client *whoop.Client, params *whoop.RequestParams) ([]Record, string, error)
+
+var fetchers = map[Collection]fetchFunc{
+	Cycles: func(ctx context.Context, client *whoop.Client, params *whoop.RequestParams) ([]Record, string, error) {
+		resp, err := client.Cycle.ListAll(ctx, params)
+		if err != nil {
+			return nil, "", err
+		}
+		records := make([]Record, len(resp.Records))
+		for i := range resp.Records {
+			c := &resp.Records[i]
+			records[i] = Record{Collection: Cycles, ID: c.ID, UpdatedAt: timeOf(c.UpdatedAt), Time: timeOf(c.Start), Data: c}
+		}
+		return records, stringOf(resp.NextToken), nil
+	},
+	Recoveries: func(ctx context.Context, client *whoop.Client, params *whoop.RequestParams) ([]Record, string, error) {
+		resp, err := client.Recovery.ListAll(ctx, params)
+		if err != nil {
+			return nil, "", err
+		}
+		records := make([]Record, len(resp.Records))
+		for i := range resp.Records {
+			r := &resp.Records[i]
+			records[i] = Record{Collection: Recoveries, ID: r.CycleID, UpdatedAt: timeOf(r.UpdatedAt), Time: timeOf(r.CreatedAt), Data: r}
+		}
+		return records, stringOf(resp.NextToken), nil
+	},
+	Sleeps: func(ctx context.Context, client *whoop.Client, params *whoop.RequestParams) ([]Record, string, error) {
+		resp, err := client.Sleep.ListAll(ctx, params)
+		if err != nil {
+			return nil, "", err
+		}
+		records := make([]Record, len(resp.Records))
+		for i := range resp.Records {
+			s := &resp.Records[i]
+			records[i] = Record{Collection: Sleeps, ID: s.ID, UpdatedAt: timeOf(s.UpdatedAt), Time: timeOf(s.Start), Data: s}
+		}
+		return records, stringOf(resp.NextToken), nil
+	},
+	Workouts: func(ctx context.Context, client *whoop.Client, params *whoop.RequestParams) ([]Record, string, error) {
+		resp, err := client.Workout.ListAll(ctx, params)
+		if err != nil {
+			return nil, "", err
+		}
+		records := make([]Record, len(resp.Records))
+		for i := range resp.Records {
+			w := &resp.Records[i]
+			records[i] = Record{Collection: Workouts, ID: w.ID, UpdatedAt: timeOf(w.UpdatedAt), Time: timeOf(w.Start), Data: w}
+		}
+		return records, stringOf(resp.NextToken), nil
+	},
+}
+
+func timeOf(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+func stringOf(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// This helper method is useful for testing purposes only.
+var now = func() time.Time {
+	return time.Now()
+}
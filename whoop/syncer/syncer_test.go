@@ -0,0 +1,125 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+)
+
+func TestEngine_Sync(t *testing.T) {
+	date := time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC)
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time {
+		return date
+	}
+
+	state := "PENDING_SCORE"
+	updatedAt := "2022-11-27T16:34:36.226Z"
+	var starts []string
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/cycle" {
+			t.Errorf("Engine.Sync(): unexpected request to %v", r.URL.Path)
+		}
+		starts = append(starts, r.URL.Query().Get("start"))
+		fmt.Fprintf(w, `{"records": [
+			{"id": 2, "updated_at": %q, "start": "2022-11-27T08:17:45.687Z", "score_state": %q},
+			{"id": 1, "updated_at": "2022-11-26T15:17:19.570Z", "start": "2022-11-26T06:49:28.470Z", "score_state": "SCORED"}
+		]}`, updatedAt, state)
+	}))
+
+	var changes []Change
+	e := &Engine{
+		Cursors: NewMemoryCursorStore(),
+		Sink: SinkFunc(func(ctx context.Context, c Change) error {
+			changes = append(changes, c)
+			return nil
+		}),
+		Lookback: 48 * time.Hour,
+	}
+	ctx := context.Background()
+
+	if err := e.Sync(ctx, "u1", client, Cycles); err != nil {
+		t.Fatalf("Engine.Sync(): expected nil error, got %v", err)
+	}
+	if len(changes) != 2 || changes[0].Op != Insert || changes[1].Op != Insert {
+		t.Fatalf("Engine.Sync(): expected 2 inserts, got %+v", changes)
+	}
+	if c, ok := changes[0].Record.Data.(*whoop.Cycle); !ok || c.ID != 2 || changes[0].UserID != "u1" {
+		t.Errorf("Engine.Sync(): expected cycle 2 for user u1, got %+v", changes[0])
+	}
+
+	// Nothing changed: no changes emitted.
+	changes = nil
+	if err := e.Sync(ctx, "u1", client, Cycles); err != nil {
+		t.Fatalf("Engine.Sync(): expected nil error, got %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("Engine.Sync(): expected no changes, got %+v", changes)
+	}
+	if want := date.Add(-48 * time.Hour).Format(time.RFC3339); starts[1] != want {
+		t.Errorf("Engine.Sync(): expected lookback start %v, got %v", want, starts[1])
+	}
+
+	// The pending cycle got scored.
+	state, updatedAt = "SCORED", "2022-11-28T10:00:00Z"
+	if err := e.Sync(ctx, "u1", client, Cycles); err != nil {
+		t.Fatalf("Engine.Sync(): expected nil error, got %v", err)
+	}
+	if len(changes) != 1 || changes[0].Op != Update || changes[0].Record.ID != 2 {
+		t.Errorf("Engine.Sync(): expected update of cycle 2, got %+v", changes)
+	}
+}
+
+func TestEngine_Sync_resume(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time {
+		return time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC)
+	}
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("nextToken") == "" {
+			fmt.Fprint(w, `{"records": [{"cycle_id": 1, "updated_at": "2022-11-27T10:00:00Z"}], "next_token": "page2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"records": [{"cycle_id": 2, "updated_at": "2022-11-26T10:00:00Z"}], "next_token": null}`)
+	}))
+
+	failing := true
+	var ids []int
+	e := &Engine{
+		Cursors: NewMemoryCursorStore(),
+		Sink: SinkFunc(func(ctx context.Context, c Change) error {
+			if c.Record.ID == 2 && failing {
+				return errors.New("sink unavailable")
+			}
+			ids = append(ids, c.Record.ID)
+			return nil
+		}),
+	}
+	ctx := context.Background()
+
+	if err := e.Sync(ctx, "u1", client, Recoveries); err == nil {
+		t.Fatal("Engine.Sync(): expected sink error, got nil")
+	}
+	failing = false
+	if err := e.Sync(ctx, "u1", client, Recoveries); err != nil {
+		t.Fatalf("Engine.Sync(): expected nil error, got %v", err)
+	}
+	if fmt.Sprint(ids) != "[1 2]" {
+		t.Errorf("Engine.Sync(): expected records [1 2] emitted once, got %v", ids)
+	}
+}
+
+func TestEngine_Sync_missingConfig(t *testing.T) {
+	e := &Engine{}
+	if err := e.Sync(context.Background(), "u1", whoop.NewClient(nil)); err == nil {
+		t.Error("Engine.Sync(): expected error for missing CursorStore and Sink, got nil")
+	}
+}
@@ -0,0 +1,286 @@
+// Package team fetches the latest data of many athletes, each with their
+// own token, for coaches managing a team.
+//
+// A Team fetches every athlete's latest cycle, recovery and sleep
+// concurrently and summarizes them in a Roster: who is in the red, yellow
+// or green recovery band, who has not synced their strap and who is
+// missing sleep data. Errors are recorded per athlete, so a revoked token
+// does not prevent the rest of the team from being fetched.
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/analytics"
+)
+
+// RequestsPerAthlete is the number of API requests made to fetch an
+// athlete.
+const RequestsPerAthlete = 3
+
+// Defaults of a Team.
+const (
+	DefaultConcurrency = 4
+	DefaultSyncWindow  = 36 * time.Hour
+)
+
+// ErrSkipped is the error of athletes who were not fetched because the
+// rate limit was reached.
+var ErrSkipped = errors.New("team: rate limit reached, athlete skipped")
+
+// now returns the current time. It is replaced in tests.
+var now = time.Now
+
+// Athlete is a member of a team.
+type Athlete struct {
+	Name   string
+	Client *whoop.Client // Client authenticated with the athlete's token.
+}
+
+// Team is a group of athletes whose latest data is fetched together.
+//
+// The rate limits of the WHOOP API apply to the application rather than to
+// each token, so a Team shares the rate limit reported to any of its
+// athletes' clients: once too few requests remain until the limit resets,
+// the remaining athletes are skipped rather than failing with
+// *whoop.RateLimitError.
+//
+// Clients may cache responses with WithCache: the latest records are
+// cached for the Pending TTL only, so the roster is at most that old.
+type Team struct {
+	Athletes []Athlete
+
+	// Concurrency is the maximum number of athletes fetched at the same
+	// time. Defaults to DefaultConcurrency.
+	Concurrency int
+
+	// SyncWindow is how recently the latest cycle of an athlete must have
+	// started for the athlete to be considered synced. Defaults to
+	// DefaultSyncWindow.
+	SyncWindow time.Duration
+
+	mu       sync.Mutex
+	rate     whoop.Rate // Latest shared rate limit.
+	reserved int        // Requests of the fetches in progress.
+}
+
+// Status is the latest data of an athlete.
+type Status struct {
+	Name     string
+	Cycle    *whoop.Cycle    // Latest cycle, if any.
+	Recovery *whoop.Recovery // Latest recovery, if any.
+	Sleep    *whoop.Sleep    // Latest sleep which is not a nap, if any.
+
+	// Err holds the errors of the requests made for the athlete, joined,
+	// or ErrSkipped. Records fetched without error are set even if other
+	// requests failed.
+	Err error
+
+	// Band is the recovery band of the latest cycle, or empty if its
+	// recovery is not scored yet or the athlete is calibrating.
+	Band analytics.Band
+
+	// NotSynced is set if the athlete has no cycle which started within
+	// the sync window.
+	NotSynced bool
+
+	// MissingSleep is set if there is no scored sleep for the latest
+	// cycle.
+	MissingSleep bool
+}
+
+// Roster is the status of every athlete of a team at a point in time.
+type Roster struct {
+	Time     time.Time
+	Athletes []Status // In the order of Team.Athletes.
+}
+
+// Fetch fetches the latest cycle, recovery and sleep of every athlete
+// concurrently and returns the roster of the team. Athletes are fetched
+// by at most Concurrency goroutines, in order, and athletes left when ctx
+// is done or the rate limit is reached have an error.
+func (t *Team) Fetch(ctx context.Context) Roster {
+	concurrency := t.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	window := t.SyncWindow
+	if window <= 0 {
+		window = DefaultSyncWindow
+	}
+	roster := Roster{Time: now(), Athletes: make([]Status, len(t.Athletes))}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(t.Athletes); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				roster.Athletes[i] = t.fetch(ctx, t.Athletes[i], roster.Time, window)
+			}
+		}()
+	}
+	for i := range t.Athletes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return roster
+}
+
+// Rate returns the latest rate limit reported to any client of the team.
+func (t *Team) Rate() whoop.Rate {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rate
+}
+
+// reserve reserves n requests of the shared rate limit. It reports false
+// if fewer than n requests remain until the limit resets, once the
+// requests of the fetches in progress are accounted for.
+func (t *Team) reserve(n int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	known := !t.rate.Reset.IsZero() && now().Before(t.rate.Reset)
+	if known && t.rate.Remaining-t.reserved < n {
+		return false
+	}
+	t.reserved += n
+	return true
+}
+
+// release releases n reserved requests and records the rate limit
+// reported by the API after they were made, if any, unless another client
+// has already reported a fresher one.
+func (t *Team) release(n int, rate whoop.Rate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.reserved -= n
+	if !rate.Reset.IsZero() && fresher(rate, t.rate) {
+		t.rate = rate
+	}
+}
+
+// resetJitter is the difference between the reset times reported for the
+// same rate limit window, which are computed from a number of seconds.
+const resetJitter = time.Second
+
+// fresher reports whether the rate limit a is more recent than b: b has
+// expired, a belongs to a later window, or a has fewer remaining requests
+// in the same window.
+func fresher(a, b whoop.Rate) bool {
+	switch {
+	case !now().Before(b.Reset):
+		return true
+	case a.Reset.After(b.Reset.Add(resetJitter)):
+		return true
+	case a.Reset.Before(b.Reset.Add(-resetJitter)):
+		return false
+	}
+	return a.Remaining < b.Remaining
+}
+
+func (t *Team) fetch(ctx context.Context, a Athlete, at time.Time, window time.Duration) Status {
+	s := Status{Name: a.Name}
+	switch {
+	case a.Client == nil:
+		s.Err = fmt.Errorf("team: athlete %q has no client", a.Name)
+	case ctx.Err() != nil:
+		s.Err = ctx.Err()
+	case !t.reserve(RequestsPerAthlete):
+		s.Err = ErrSkipped
+	default:
+		s.Err = latest(ctx, a.Client, &s)
+		rate := a.Client.Rate()
+		var rateErr *whoop.RateLimitError
+		if errors.As(s.Err, &rateErr) {
+			rate = whoop.Rate{Reset: rateErr.Rate.Reset}
+		}
+		t.release(RequestsPerAthlete, rate)
+	}
+	s.classify(at, window)
+	return s
+}
+
+// latest sets the latest cycle, recovery and sleep of s from client and
+// returns the errors of the requests, joined.
+func latest(ctx context.Context, client *whoop.Client, s *Status) error {
+	var errs []error
+	if resp, err := client.Cycle.ListAll(ctx, &whoop.RequestParams{Limit: 1}); err != nil {
+		errs = append(errs, fmt.Errorf("cycles: %w", err))
+	} else if len(resp.Records) > 0 {
+		s.Cycle = &resp.Records[0]
+	}
+	if resp, err := client.Recovery.ListAll(ctx, &whoop.RequestParams{Limit: 1}); err != nil {
+		errs = append(errs, fmt.Errorf("recoveries: %w", err))
+	} else if len(resp.Records) > 0 {
+		s.Recovery = &resp.Records[0]
+	}
+	// Fetch a few sleeps so that a nap does not hide the latest night.
+	if resp, err := client.Sleep.ListAll(ctx, &whoop.RequestParams{Limit: 5}); err != nil {
+		errs = append(errs, fmt.Errorf("sleeps: %w", err))
+	} else {
+		for i := range resp.Records {
+			if !resp.Records[i].Nap {
+				s.Sleep = &resp.Records[i]
+				break
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// classify sets the band, sync and sleep flags of s at the time at.
+func (s *Status) classify(at time.Time, window time.Duration) {
+	c := s.Cycle
+	s.NotSynced = c == nil || c.Start == nil || at.Sub(*c.Start) > window
+	if c == nil {
+		s.MissingSleep = true
+		return
+	}
+	if r := s.Recovery; r != nil && r.CycleID == c.ID && r.Scored() && !r.Score.UserCalibrating {
+		s.Band = analytics.RecoveryBand(r.Score.RecoveryScore)
+	}
+	// The sleep of a cycle starts it, give or take the minutes a sleep
+	// can be adjusted by.
+	sl := s.Sleep
+	s.MissingSleep = sl == nil || sl.Start == nil || c.Start == nil || !sl.Scored() || sl.Start.Before(c.Start.Add(-time.Hour))
+}
+
+// ByBand returns the athletes in the recovery band b.
+func (r Roster) ByBand(b analytics.Band) []Status {
+	return r.filter(func(s *Status) bool { return s.Band == b })
+}
+
+// NotSynced returns the athletes who have not synced within the sync
+// window.
+func (r Roster) NotSynced() []Status {
+	return r.filter(func(s *Status) bool { return s.NotSynced })
+}
+
+// MissingSleep returns the athletes without a scored sleep for their
+// latest cycle.
+func (r Roster) MissingSleep() []Status {
+	return r.filter(func(s *Status) bool { return s.MissingSleep })
+}
+
+// Failed returns the athletes whose data could not be fully fetched.
+func (r Roster) Failed() []Status {
+	return r.filter(func(s *Status) bool { return s.Err != nil })
+}
+
+func (r Roster) filter(keep func(*Status) bool) []Status {
+	var statuses []Status
+	for i := range r.Athletes {
+		if keep(&r.Athletes[i]) {
+			statuses = append(statuses, r.Athletes[i])
+		}
+	}
+	return statuses
+}
@@ -0,0 +1,274 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ferueda/go-whoop/internal/whooptest"
+	"github.com/ferueda/go-whoop/whoop"
+	"github.com/ferueda/go-whoop/whoop/analytics"
+)
+
+// athlete returns a handler serving a cycle starting at cycleStart, its
+// recovery and a sleep starting at sleepStart.
+func athlete(cycleStart string, recovery float64, sleepStart string, nap bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/cycle"):
+			fmt.Fprintf(w, `{"records": [{"id": 1, "user_id": 10, "start": %q, "score_state": "SCORED", "score": {"strain": 8.5}}]}`, cycleStart)
+		case strings.HasSuffix(r.URL.Path, "/recovery"):
+			fmt.Fprintf(w, `{"records": [{"cycle_id": 1, "sleep_id": 2, "user_id": 10, "score_state": "SCORED", "score": {"recovery_score": %v}}]}`, recovery)
+		case strings.HasSuffix(r.URL.Path, "/sleep"):
+			fmt.Fprintf(w, `{"records": [{"id": 2, "user_id": 10, "start": %q, "nap": %v, "score_state": "SCORED"}]}`, sleepStart, nap)
+		}
+	}
+}
+
+func TestTeam_Fetch(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC) }
+	team := &Team{Athletes: []Athlete{
+		{Name: "alice", Client: whooptest.NewClient(t, athlete("2022-11-27T23:00:00Z", 80, "2022-11-27T23:00:00Z", false))},
+		{Name: "bob", Client: whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusUnauthorized) }))},
+		{Name: "carol", Client: whooptest.NewClient(t, athlete("2022-11-25T23:00:00Z", 20, "2022-11-27T13:00:00Z", true))},
+		{Name: "dave", Client: whooptest.NewClient(t, athlete("2022-11-27T23:00:00Z", 50, "2022-11-26T23:00:00Z", false))},
+		{Name: "erin"},
+	}}
+
+	roster := team.Fetch(context.Background())
+
+	if !roster.Time.Equal(now()) || len(roster.Athletes) != 5 {
+		t.Fatalf("Fetch(): got %d athletes at %v, want 5 at %v", len(roster.Athletes), roster.Time, now())
+	}
+	for i, name := range []string{"alice", "bob", "carol", "dave", "erin"} {
+		if got := roster.Athletes[i].Name; got != name {
+			t.Errorf("Fetch(): got athlete %d %q, want %q", i, got, name)
+		}
+	}
+	alice := roster.Athletes[0]
+	if alice.Err != nil || alice.Band != analytics.Green || alice.NotSynced || alice.MissingSleep || alice.Cycle == nil || alice.Sleep == nil {
+		t.Errorf("Fetch(): got %+v for alice, want a synced green athlete with sleep", alice)
+	}
+	if err := roster.Athletes[1].Err; !errors.Is(err, whoop.ErrUnauthorized) {
+		t.Errorf("Fetch(): got error %v for bob, want %v", err, whoop.ErrUnauthorized)
+	}
+
+	names := func(statuses []Status) string {
+		var n []string
+		for _, s := range statuses {
+			n = append(n, s.Name)
+		}
+		return strings.Join(n, ",")
+	}
+	tests := []struct {
+		name string
+		got  []Status
+		want string
+	}{
+		{"ByBand(Green)", roster.ByBand(analytics.Green), "alice"},
+		{"ByBand(Yellow)", roster.ByBand(analytics.Yellow), "dave"},
+		{"ByBand(Red)", roster.ByBand(analytics.Red), "carol"},
+		{"NotSynced", roster.NotSynced(), "bob,carol,erin"},
+		{"MissingSleep", roster.MissingSleep(), "bob,carol,dave,erin"},
+		{"Failed", roster.Failed(), "bob,erin"},
+	}
+	for _, tt := range tests {
+		if got := names(tt.got); got != tt.want {
+			t.Errorf("Roster.%s(): got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTeam_Fetch_rateLimit(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC) }
+	var requests atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprint(10-n))
+		w.Header().Set("X-RateLimit-Reset", "60")
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	team := &Team{Concurrency: 1}
+	for _, name := range []string{"alice", "bob", "carol", "dave"} {
+		team.Athletes = append(team.Athletes, Athlete{Name: name, Client: whooptest.NewClient(t, handler)})
+	}
+
+	roster := team.Fetch(context.Background())
+
+	if got := requests.Load(); got != 9 {
+		t.Errorf("Fetch(): got %d requests, want 9", got)
+	}
+	for i, s := range roster.Athletes {
+		var want error
+		if i == 3 {
+			want = ErrSkipped
+		}
+		if s.Err != want {
+			t.Errorf("Fetch(): got error %v for %s, want %v", s.Err, s.Name, want)
+		}
+	}
+	if got := team.Rate().Remaining; got != 1 {
+		t.Errorf("Rate(): got %d remaining, want 1", got)
+	}
+}
+
+func TestTeam_Fetch_concurrency(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC) }
+	var mu sync.Mutex
+	inFlight, max := 0, 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > max {
+			max = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		fmt.Fprint(w, `{"records": []}`)
+	})
+	team := &Team{Concurrency: 2}
+	for i := 0; i < 6; i++ {
+		team.Athletes = append(team.Athletes, Athlete{Name: fmt.Sprint(i), Client: whooptest.NewClient(t, handler)})
+	}
+
+	roster := team.Fetch(context.Background())
+
+	if len(roster.Failed()) != 0 {
+		t.Errorf("Fetch(): got failures %+v, want none", roster.Failed())
+	}
+	if max > 2 {
+		t.Errorf("Fetch(): got %d concurrent requests, want at most 2", max)
+	}
+}
+
+func TestTeam_Fetch_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	team := &Team{Athletes: []Athlete{{Name: "alice", Client: whoop.NewClient(nil)}}}
+
+	roster := team.Fetch(ctx)
+
+	if err := roster.Athletes[0].Err; !errors.Is(err, context.Canceled) {
+		t.Errorf("Fetch(): got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestTeam_release(t *testing.T) {
+	date := time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC)
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return date }
+
+	reset := date.Add(30 * time.Second)
+	testCases := []struct {
+		rate whoop.Rate
+		want whoop.Rate
+	}{
+		{whoop.Rate{Remaining: 50, Reset: reset}, whoop.Rate{Remaining: 50, Reset: reset}},
+		// A stale reading of the same window doesn't add requests back.
+		{whoop.Rate{Remaining: 60, Reset: reset.Add(-500 * time.Millisecond)}, whoop.Rate{Remaining: 50, Reset: reset}},
+		{whoop.Rate{Remaining: 40, Reset: reset.Add(500 * time.Millisecond)}, whoop.Rate{Remaining: 40, Reset: reset.Add(500 * time.Millisecond)}},
+		// A reading of an earlier window is stale.
+		{whoop.Rate{Remaining: 90, Reset: date.Add(-time.Minute)}, whoop.Rate{Remaining: 40, Reset: reset.Add(500 * time.Millisecond)}},
+		// A later window starts afresh.
+		{whoop.Rate{Remaining: 99, Reset: reset.Add(time.Minute)}, whoop.Rate{Remaining: 99, Reset: reset.Add(time.Minute)}},
+		{whoop.Rate{}, whoop.Rate{Remaining: 99, Reset: reset.Add(time.Minute)}},
+	}
+
+	team := &Team{}
+	for _, test := range testCases {
+		team.reserve(1)
+		team.release(1, test.rate)
+		if got := team.Rate(); got != test.want {
+			t.Errorf("release(%v): got rate %v, want %v", test.rate, got, test.want)
+		}
+	}
+
+	// Once the window has passed, any reading replaces it.
+	now = func() time.Time { return date.Add(2 * time.Minute) }
+	old := whoop.Rate{Remaining: 10, Reset: date.Add(90 * time.Second)}
+	team.release(0, old)
+	if got := team.Rate(); got != old {
+		t.Errorf("release(%v): got rate %v, want %v", old, got, old)
+	}
+}
+
+func TestStatus_classify(t *testing.T) {
+	at := time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2022, 11, 27, 23, 0, 0, 0, time.UTC)
+	cycle := &whoop.Cycle{ID: 1, Start: &start}
+	scored := func(score float64, calibrating bool) *whoop.Recovery {
+		r := &whoop.Recovery{CycleID: 1, ScoreState: whooptest.Ptr("SCORED")}
+		r.Score.RecoveryScore = score
+		r.Score.UserCalibrating = calibrating
+		return r
+	}
+	sleep := &whoop.Sleep{Start: &start, ScoreState: whooptest.Ptr("SCORED")}
+
+	tests := []struct {
+		name   string
+		status Status
+		band   analytics.Band
+		synced bool
+		slept  bool
+	}{
+		{"complete", Status{Cycle: cycle, Recovery: scored(70, false), Sleep: sleep}, analytics.Green, true, true},
+		{"calibrating", Status{Cycle: cycle, Recovery: scored(70, true), Sleep: sleep}, "", true, true},
+		{"previous recovery", Status{Cycle: cycle, Recovery: &whoop.Recovery{CycleID: 0, ScoreState: whooptest.Ptr("SCORED")}, Sleep: sleep}, "", true, true},
+		{"pending sleep", Status{Cycle: cycle, Recovery: scored(20, false), Sleep: &whoop.Sleep{Start: &start, ScoreState: whooptest.Ptr("PENDING_SCORE")}}, analytics.Red, true, false},
+		{"no cycle", Status{}, "", false, false},
+	}
+	for _, tt := range tests {
+		s := tt.status
+		s.classify(at, DefaultSyncWindow)
+		if s.Band != tt.band || s.NotSynced == tt.synced || s.MissingSleep == tt.slept {
+			t.Errorf("classify(%s): got band %q, not synced %v, missing sleep %v, want %q, %v, %v", tt.name, s.Band, s.NotSynced, s.MissingSleep, tt.band, !tt.synced, !tt.slept)
+		}
+	}
+}
+
+func TestTeam_Fetch_cache(t *testing.T) {
+	oldNow := now
+	t.Cleanup(func() { now = oldNow })
+	now = func() time.Time { return time.Date(2022, 11, 28, 12, 0, 0, 0, time.UTC) }
+	var mu sync.Mutex
+	handler := athlete("2022-11-26T23:00:00Z", 20, "2022-11-26T23:00:00Z", false)
+	client := whooptest.NewClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		h := handler
+		mu.Unlock()
+		h(w, r)
+	})).WithCache(whoop.NewLRUCache(10), whoop.CacheTTL{Pending: time.Nanosecond})
+	team := &Team{Athletes: []Athlete{{Name: "alice", Client: client}}}
+
+	if got := team.Fetch(context.Background()).Athletes[0]; got.Band != analytics.Red {
+		t.Fatalf("Fetch(): got band %q, want %q", got.Band, analytics.Red)
+	}
+
+	// A new cycle, recovery and sleep land. The latest records must not
+	// be served from the cache for the scored TTL.
+	mu.Lock()
+	handler = athlete("2022-11-27T23:00:00Z", 80, "2022-11-27T23:00:00Z", false)
+	mu.Unlock()
+	time.Sleep(time.Millisecond)
+
+	got := team.Fetch(context.Background()).Athletes[0]
+	if got.Err != nil || got.Band != analytics.Green || got.MissingSleep || got.NotSynced {
+		t.Errorf("Fetch(): got %+v, want the new green recovery and sleep", got)
+	}
+}
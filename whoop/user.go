@@ -2,6 +2,7 @@ package whoop
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 )
 
@@ -21,6 +22,31 @@ type UserProfile struct {
 	Email     *string `json:"email,omitempty"`      // User's Email.
 	FirstName *string `json:"first_name,omitempty"` // User's First Name.
 	LastName  *string `json:"last_name,omitempty"`  // User's Last Name
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields returned by the API that are not part of the model, keyed by their dotted path.
+}
+
+// UnmarshalJSON unmarshals data into p, keeping unknown fields in p.Extra.
+func (p *UserProfile) UnmarshalJSON(data []byte) error {
+	type userProfile UserProfile
+	var v userProfile
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*p = UserProfile(v)
+	p.Extra = extra
+	return nil
+}
+
+// MarshalJSON marshals p, including the unknown fields in p.Extra.
+func (p UserProfile) MarshalJSON() ([]byte, error) {
+	type userProfile UserProfile
+	return marshalWithExtra(userProfile(p), p.Extra)
+}
+
+func (p UserProfile) unknownFields() []string {
+	return extraKeys("", p.Extra)
 }
 
 // Body measurements about the user, such as their weight and height.
@@ -30,6 +56,31 @@ type BodyMeasurement struct {
 	HeightMeter    float64 `json:"height_meter,omitempty"`    // User's height in meters.
 	WeightKilogram float64 `json:"weight_kilogram,omitempty"` // User's weight in kilograms.
 	MaxHeartRate   int     `json:"max_heart_rate,omitempty"`  // The max heart rate WHOOP calculated for the user.
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields returned by the API that are not part of the model, keyed by their dotted path.
+}
+
+// UnmarshalJSON unmarshals data into b, keeping unknown fields in b.Extra.
+func (b *BodyMeasurement) UnmarshalJSON(data []byte) error {
+	type bodyMeasurement BodyMeasurement
+	var v bodyMeasurement
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*b = BodyMeasurement(v)
+	b.Extra = extra
+	return nil
+}
+
+// MarshalJSON marshals b, including the unknown fields in b.Extra.
+func (b BodyMeasurement) MarshalJSON() ([]byte, error) {
+	type bodyMeasurement BodyMeasurement
+	return marshalWithExtra(bodyMeasurement(b), b.Extra)
+}
+
+func (b BodyMeasurement) unknownFields() []string {
+	return extraKeys("", b.Extra)
 }
 
 // GetProfile retrieves the profile for the authenticated user.
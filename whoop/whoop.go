@@ -6,12 +6,15 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/go-querystring/query"
@@ -36,8 +39,16 @@ type Client struct {
 	baseURL    *url.URL     // Base URL for API requests.
 	apiVersion string
 
+	rateMu    sync.Mutex
 	rateLimit Rate // Rate limit for the client as determined by the most recent API call.
 
+	logger *slog.Logger // Logger for request diagnostics. Nil disables logging.
+
+	cache    Cache    // Cache for GET responses. Nil disables caching.
+	cacheTTL CacheTTL // Freshness of cached responses.
+
+	strict bool // Return an error for unknown fields in API responses.
+
 	shared service // Reuse a single struct instead of allocating one for each service on the heap.
 
 	// Services used for talking to different parts of the API.
@@ -70,6 +81,74 @@ func NewClient(httpClient *http.Client) *Client {
 	return c
 }
 
+// WithLogger sets the logger used by the client to emit structured
+// debug logs for each request, and warnings when a request is skipped
+// because the rate limit has been reached. It returns the client to
+// allow chaining with NewClient. A nil logger disables logging.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	c.logger = logger
+	return c
+}
+
+// WithBaseURL sets the base URL of API requests, such as a proxy or a test
+// server, in place of the WHOOP API. A trailing slash is added to its path
+// if missing. It returns the client to allow chaining with NewClient.
+func (c *Client) WithBaseURL(u *url.URL) *Client {
+	base := *u
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	c.baseURL = &base
+	return c
+}
+
+// Rate returns the rate limit for the client as determined by the most
+// recent API call. It is safe to call concurrently with API calls.
+func (c *Client) Rate() Rate {
+	c.rateMu.Lock()
+	defer c.rateMu.Unlock()
+	return c.rateLimit
+}
+
+func (c *Client) setRate(rate Rate) {
+	c.rateMu.Lock()
+	c.rateLimit = rate
+	c.rateMu.Unlock()
+}
+
+// redactedParams lists query parameters whose values are never logged.
+var redactedParams = map[string]bool{
+	"nextToken":    true,
+	"access_token": true,
+}
+
+// redactQuery returns the encoded query of u with the values of
+// sensitive parameters replaced.
+func redactQuery(u *url.URL) string {
+	q := u.Query()
+	for k := range q {
+		if redactedParams[k] {
+			q.Set(k, "REDACTED")
+		}
+	}
+	return q.Encode()
+}
+
+// ParseOffset returns the fixed zone of a timezone offset such as
+// "-08:00", as found in the TimezoneOffset of records. It returns UTC for
+// a nil or invalid offset.
+func ParseOffset(offset *string) *time.Location {
+	if offset == nil {
+		return time.UTC
+	}
+	t, err := time.Parse("-07:00", *offset)
+	if err != nil {
+		return time.UTC
+	}
+	_, secs := t.Zone()
+	return time.FixedZone(*offset, secs)
+}
+
 // RequestParams represents a GET requests query parameters
 type RequestParams struct {
 	Start     time.Time // Start time query filter
@@ -167,6 +246,10 @@ func newResponse(r *http.Response) *Response {
 
 // checkResponse checks the API response for errors, and returns them if any.
 // API response are considered an error if it has a status 200 > code >299.
+//
+// The returned *Error matches one of ErrBadRequest, ErrUnauthorized,
+// ErrForbidden, ErrNotFound or ErrServer with errors.Is, depending on
+// the response status code.
 func checkResponse(r *http.Response) error {
 	if r.StatusCode >= 200 && r.StatusCode <= 299 {
 		return nil
@@ -179,17 +262,49 @@ func checkResponse(r *http.Response) error {
 			Message:  fmt.Sprintf("API rate limit has been reached or exceeded. Please try again after %v", rateLimit.Reset.Format("2006-01-02T15:04:05")),
 		}
 	}
+	e := &Error{Code: r.StatusCode, Response: r}
+	if r.Request != nil {
+		e.Method = r.Request.Method
+		e.URL = r.Request.URL.String()
+	}
 	data, err := io.ReadAll(r.Body)
 	if err != nil {
-		return &Error{
-			Code:    http.StatusInternalServerError,
-			Message: "could not decode error",
+		e.Message = fmt.Sprintf("could not read error body: %v", err)
+	} else {
+		e.Message = string(data)
+	}
+
+	var apiErr APIError
+	if err == nil && json.Unmarshal(data, &apiErr) == nil && apiErr != (APIError{}) {
+		e.Body = &apiErr
+		if msg := apiErr.message(); msg != "" {
+			e.Message = msg
 		}
 	}
-	return &Error{
-		Code:    r.StatusCode,
-		Message: string(data),
+	if r.StatusCode == http.StatusForbidden || r.StatusCode == http.StatusUnauthorized {
+		e.MissingScopes = parseMissingScopes(r.Header.Get("WWW-Authenticate"))
 	}
+	return e
+}
+
+// parseMissingScopes extracts the required scopes from a Bearer
+// WWW-Authenticate challenge reporting an insufficient_scope error,
+// as described in RFC 6750 section 3.
+func parseMissingScopes(challenge string) []string {
+	if !strings.Contains(challenge, "insufficient_scope") {
+		return nil
+	}
+	const key = `scope="`
+	i := strings.Index(challenge, key)
+	if i < 0 {
+		return nil
+	}
+	rest := challenge[i+len(key):]
+	j := strings.IndexByte(rest, '"')
+	if j < 0 {
+		return nil
+	}
+	return strings.Fields(rest[:j])
 }
 
 // Rate represents the rate limit for the current client.
@@ -229,7 +344,16 @@ func parseRateLimit(r *http.Response) Rate {
 // Note that it skips making actual network requests
 // if rate limits have been reached or exceeded.
 func (c *Client) checkRateLimit(req *http.Request) *RateLimitError {
-	if !c.rateLimit.Reset.IsZero() && c.rateLimit.Remaining <= 0 && now().Before(c.rateLimit.Reset) {
+	rate := c.Rate()
+	if !rate.Reset.IsZero() && rate.Remaining <= 0 && now().Before(rate.Reset) {
+		if c.logger != nil {
+			c.logger.LogAttrs(req.Context(), slog.LevelWarn, "whoop: rate limit reached, skipping request",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("rate_remaining", rate.Remaining),
+				slog.Time("rate_reset", rate.Reset),
+			)
+		}
 		// Create a fake response.
 		resp := &http.Response{
 			Status:     http.StatusText(http.StatusTooManyRequests),
@@ -239,9 +363,9 @@ func (c *Client) checkRateLimit(req *http.Request) *RateLimitError {
 			Body:       io.NopCloser(strings.NewReader("")),
 		}
 		return &RateLimitError{
-			Rate:     c.rateLimit,
+			Rate:     rate,
 			Response: resp,
-			Message:  fmt.Sprintf("API rate limit has been reached or exceeded. Please try again after %v", c.rateLimit.Reset.Format("2006-01-02T15:04:05")),
+			Message:  fmt.Sprintf("API rate limit has been reached or exceeded. Please try again after %v", rate.Reset.Format("2006-01-02T15:04:05")),
 		}
 	}
 	return nil
@@ -251,21 +375,73 @@ func (c *Client) checkRateLimit(req *http.Request) *RateLimitError {
 // The response body will be unmarshalled into v,
 // or return an error if an API error occurred.
 func (c *Client) do(req *http.Request, v any) error {
+	entry, cached := c.cacheLookup(req)
+	if cached && now().Before(entry.Expires) {
+		if c.logger != nil {
+			c.logger.LogAttrs(req.Context(), slog.LevelDebug, "whoop: cache hit",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.String("query", redactQuery(req.URL)),
+			)
+		}
+		return c.decode(bytes.NewReader(entry.Body), v)
+	}
+	if cached && entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
 	if err := c.checkRateLimit(req); err != nil {
 		return err
 	}
+	start := time.Now()
 	resp, err := c.http.Do(req)
 	if err != nil {
+		if c.logger != nil {
+			c.logger.LogAttrs(req.Context(), slog.LevelDebug, "whoop: request failed",
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.String("query", redactQuery(req.URL)),
+				slog.Duration("duration", time.Since(start)),
+				slog.Any("error", err),
+			)
+		}
 		return err
 	}
 	defer resp.Body.Close()
-	err = checkResponse(resp)
 	response := newResponse(resp)
+	if c.logger != nil {
+		c.logger.LogAttrs(req.Context(), slog.LevelDebug, "whoop: request",
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.String("query", redactQuery(req.URL)),
+			slog.Int("status", resp.StatusCode),
+			slog.Duration("duration", time.Since(start)),
+			slog.Int("rate_remaining", response.Rate.Remaining),
+		)
+	}
+	if cached && entry.ETag != "" && resp.StatusCode == http.StatusNotModified {
+		c.setRate(response.Rate)
+		if err := c.decode(bytes.NewReader(entry.Body), v); err != nil {
+			return err
+		}
+		c.cacheStore(req, entry.ETag, entry.Body, v)
+		return nil
+	}
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	c.setRate(response.Rate)
+	if c.cache == nil {
+		return c.decode(response.Body, v)
+	}
+	body, err := io.ReadAll(response.Body)
 	if err != nil {
 		return err
 	}
-	c.rateLimit = response.Rate
-	return json.NewDecoder(response.Body).Decode(v)
+	if err := c.decode(bytes.NewReader(body), v); err != nil {
+		return err
+	}
+	c.cacheStore(req, resp.Header.Get("ETag"), body, v)
+	return nil
 }
 
 // get makes a GET request to the given url. The response body will be
@@ -278,16 +454,72 @@ func (c *Client) get(ctx context.Context, url string, v any) error {
 	return c.do(req, v)
 }
 
+// Sentinel errors matched by *Error with errors.Is, based on
+// the HTTP status code of the API response.
+var (
+	ErrBadRequest   = errors.New("whoop: bad request")  // 400 Bad Request.
+	ErrUnauthorized = errors.New("whoop: unauthorized") // 401 Unauthorized, e.g. an expired or revoked token.
+	ErrForbidden    = errors.New("whoop: forbidden")    // 403 Forbidden, e.g. a token missing a required scope.
+	ErrNotFound     = errors.New("whoop: not found")    // 404 Not Found.
+	ErrServer       = errors.New("whoop: server error") // Any 5xx status code.
+)
+
 // Error represents an error returned by the WHOOP API.
 type Error struct {
 	Code    int    `json:"code"`    // The HTTP status code.
 	Message string `json:"message"` // A short description of the error.
+
+	Method   string         `json:"-"` // HTTP method of the request that caused the error.
+	URL      string         `json:"-"` // URL of the request that caused the error.
+	Response *http.Response `json:"-"` // HTTP response that caused the error.
+
+	Body          *APIError `json:"-"` // Parsed error body, if the API returned one.
+	MissingScopes []string  `json:"-"` // OAuth scopes the token lacks, if reported by the API.
 }
 
 func (r *Error) Error() string {
+	if r.Method != "" {
+		return fmt.Sprintf("%v %v: %v error. %v", r.Method, r.URL, r.Code, r.Message)
+	}
 	return fmt.Sprintf("%v error. %v", r.Code, r.Message)
 }
 
+// Is reports whether r matches the sentinel error target for
+// its status code, e.g. ErrNotFound for a 404 response.
+func (r *Error) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return r.Code == http.StatusBadRequest
+	case ErrUnauthorized:
+		return r.Code == http.StatusUnauthorized
+	case ErrForbidden:
+		return r.Code == http.StatusForbidden
+	case ErrNotFound:
+		return r.Code == http.StatusNotFound
+	case ErrServer:
+		return r.Code >= 500 && r.Code <= 599
+	}
+	return false
+}
+
+// APIError represents the JSON error body returned by the WHOOP API.
+type APIError struct {
+	Error            string `json:"error,omitempty"`             // Error code, e.g. "invalid_token".
+	ErrorDescription string `json:"error_description,omitempty"` // Human readable description of the error.
+	Message          string `json:"message,omitempty"`           // Error message.
+}
+
+// message returns the most descriptive message in e.
+func (e APIError) message() string {
+	switch {
+	case e.Message != "":
+		return e.Message
+	case e.ErrorDescription != "":
+		return e.ErrorDescription
+	}
+	return e.Error
+}
+
 // RateLimitError occurs when the API returns 429 Too Many Requests response
 // with a rate limit remaining value of 0.
 type RateLimitError struct {
@@ -1,9 +1,12 @@
 package whoop
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -26,6 +29,28 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestClient_WithBaseURL(t *testing.T) {
+	testCases := []struct {
+		base string
+		want string
+	}{
+		{"http://localhost:8080", "http://localhost:8080/v1/cycle"},
+		{"http://localhost:8080/proxy", "http://localhost:8080/proxy/v1/cycle"},
+		{"http://localhost:8080/proxy/", "http://localhost:8080/proxy/v1/cycle"},
+	}
+
+	for _, test := range testCases {
+		u, _ := url.Parse(test.base)
+		req, err := NewClient(nil).WithBaseURL(u).newRequest(context.Background(), http.MethodGet, "/cycle", nil)
+		if err != nil {
+			t.Fatalf("newRequest(): expected nil error, got %v", err)
+		}
+		if got := req.URL.String(); got != test.want {
+			t.Errorf("WithBaseURL(): got %v, want %v", got, test.want)
+		}
+	}
+}
+
 func TestNewRequest(t *testing.T) {
 	c := NewClient(nil)
 
@@ -157,6 +182,90 @@ func TestCheckResponse_TooManyRequests(t *testing.T) {
 	}
 }
 
+func TestCheckResponse_errorTypes(t *testing.T) {
+	u, _ := url.Parse("https://api.prod.whoop.com/developer/v1/cycle/1")
+	testCases := []struct {
+		statusCode int
+		header     string
+		body       string
+		want       error
+		message    string
+		scopes     []string
+	}{
+		{400, "", `{"message":"invalid limit"}`, ErrBadRequest, "invalid limit", nil},
+		{401, "", `{"error":"invalid_token","error_description":"token revoked"}`, ErrUnauthorized, "token revoked", nil},
+		{403, `Bearer error="insufficient_scope", scope="read:recovery read:sleep"`, "", ErrForbidden, "", []string{"read:recovery", "read:sleep"}},
+		{404, "", "not found", ErrNotFound, "not found", nil},
+		{503, "", "unavailable", ErrServer, "unavailable", nil},
+	}
+
+	for _, test := range testCases {
+		res := http.Response{
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(test.body)),
+			StatusCode: test.statusCode,
+			Request:    &http.Request{Method: http.MethodGet, URL: u},
+		}
+		if test.header != "" {
+			res.Header.Set("WWW-Authenticate", test.header)
+		}
+
+		got := checkResponse(&res)
+		if !errors.Is(got, test.want) {
+			t.Errorf("checkResponse(): got %v, want errors.Is %v", got, test.want)
+		}
+		var apiErr *Error
+		if !errors.As(got, &apiErr) {
+			t.Fatalf("checkResponse(): expected *Error; got %#v", got)
+		}
+		if apiErr.Message != test.message {
+			t.Errorf("checkResponse(): Message is %q, want %q", apiErr.Message, test.message)
+		}
+		if apiErr.Method != http.MethodGet || apiErr.URL != u.String() || apiErr.Response != &res {
+			t.Errorf("checkResponse(): got request info %v %v, want %v %v", apiErr.Method, apiErr.URL, http.MethodGet, u)
+		}
+		if fmt.Sprint(apiErr.MissingScopes) != fmt.Sprint(test.scopes) {
+			t.Errorf("checkResponse(): MissingScopes is %v, want %v", apiErr.MissingScopes, test.scopes)
+		}
+		for _, other := range []error{ErrBadRequest, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrServer} {
+			if other != test.want && errors.Is(got, other) {
+				t.Errorf("checkResponse(): %v unexpectedly matches %v", got, other)
+			}
+		}
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestCheckResponse_bodyReadError(t *testing.T) {
+	for _, test := range []struct {
+		statusCode int
+		want       error
+	}{
+		{401, ErrUnauthorized},
+		{404, ErrNotFound},
+		{503, ErrServer},
+	} {
+		res := http.Response{Header: http.Header{}, Body: io.NopCloser(errReader{}), StatusCode: test.statusCode}
+
+		got := checkResponse(&res)
+		var apiErr *Error
+		if !errors.As(got, &apiErr) {
+			t.Fatalf("checkResponse(): expected *Error; got %#v", got)
+		}
+		if apiErr.Code != test.statusCode || !errors.Is(got, test.want) {
+			t.Errorf("checkResponse(): got code %v matching %v, want %v matching %v", apiErr.Code, got, test.statusCode, test.want)
+		}
+		if want := "could not read error body: connection reset"; apiErr.Message != want {
+			t.Errorf("checkResponse(): Message is %q, want %q", apiErr.Message, want)
+		}
+	}
+}
+
 func TestAddParams(t *testing.T) {
 	start := time.Date(2022, 1, 1, 12, 30, 20, 0, time.UTC)
 	end := time.Date(2022, 5, 20, 6, 0, 10, 10, time.UTC)
@@ -185,6 +294,26 @@ func TestAddParams(t *testing.T) {
 	}
 }
 
+func TestParseOffset(t *testing.T) {
+	offset, invalid := "-08:00", "PST"
+	testCases := []struct {
+		offset *string
+		name   string
+		secs   int
+	}{
+		{nil, "UTC", 0},
+		{&invalid, "UTC", 0},
+		{&offset, "-08:00", -8 * 60 * 60},
+	}
+
+	for _, test := range testCases {
+		name, secs := time.Date(2022, 1, 1, 0, 0, 0, 0, ParseOffset(test.offset)).Zone()
+		if name != test.name || secs != test.secs {
+			t.Errorf("ParseOffset(): got %v %v, want %v %v", name, secs, test.name, test.secs)
+		}
+	}
+}
+
 func TestCheckRateLimit(t *testing.T) {
 	url, _ := url.Parse("/test")
 	date := now()
@@ -276,6 +405,9 @@ func TestDo_rateLimit(t *testing.T) {
 	if client.rateLimit.Reset != now().Add(time.Second*30) {
 		t.Errorf("do(): expected rateLimit.Reset %v; got %v.", now().Add(time.Second*30), client.rateLimit.Reset)
 	}
+	if got := client.Rate(); got != client.rateLimit {
+		t.Errorf("Rate(): got %v, want %v", got, client.rateLimit)
+	}
 }
 func TestDo_rateLimit_error(t *testing.T) {
 	client, mux, _, teardown := setup()
@@ -318,6 +450,55 @@ func TestDo_BadRequest(t *testing.T) {
 	if err, ok := got.(*Error); !ok || err.Code != http.StatusBadRequest {
 		t.Errorf("do(): expected HTTP 400 error; got %#v.", got)
 	}
+	if !errors.Is(got, ErrBadRequest) {
+		t.Errorf("do(): expected error to match ErrBadRequest; got %v.", got)
+	}
+}
+
+func TestDo_logger(t *testing.T) {
+	client, mux, _, teardown := setup()
+	defer teardown()
+
+	var buf bytes.Buffer
+	client.WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	mux.HandleFunc("/"+apiVersion+"/test", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerRateRemaining, "42")
+		w.Header().Set(headerRateReset, "30")
+		fmt.Fprint(w, `{}`)
+	})
+
+	ctx := context.Background()
+	u, _ := addParams("/test", &RequestParams{Limit: 5, NextToken: "secret_token"})
+	req, _ := client.newRequest(ctx, http.MethodGet, u, nil)
+	if err := client.do(req, &struct{}{}); err != nil {
+		t.Fatalf("do(): got unexpected error %#v", err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{"level=DEBUG", "method=GET", "path=/v1/test", "status=200", "rate_remaining=42", "limit=5", "nextToken=REDACTED"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("do(): log output %q does not contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "secret_token") {
+		t.Errorf("do(): log output %q contains the next page token", got)
+	}
+}
+
+func TestCheckRateLimit_logger(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewClient(nil).WithLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+	c.rateLimit.Remaining = 0
+	c.rateLimit.Reset = now().Add(time.Hour)
+
+	req, _ := c.newRequest(context.Background(), http.MethodGet, "/test", nil)
+	if got := c.checkRateLimit(req); got == nil {
+		t.Fatal("checkRateLimit(): expected RateLimitError error; got nil")
+	}
+	if got := buf.String(); !strings.Contains(got, "level=WARN") || !strings.Contains(got, "rate_remaining=0") {
+		t.Errorf("checkRateLimit(): expected warning log, got %q", got)
+	}
 }
 
 func testHttpMethod(t *testing.T, r *http.Request, want string) {
@@ -329,8 +510,7 @@ func testHttpMethod(t *testing.T, r *http.Request, want string) {
 func setup() (*Client, *http.ServeMux, string, func()) {
 	handler := http.NewServeMux()
 	server := httptest.NewServer(handler)
-	client := NewClient(nil)
 	url, _ := url.Parse(server.URL)
-	client.baseURL = url
+	client := NewClient(nil).WithBaseURL(url)
 	return client, handler, server.URL, server.Close
 }
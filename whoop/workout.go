@@ -2,6 +2,7 @@ package whoop
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"time"
 )
@@ -50,6 +51,53 @@ type Workout struct {
 			ZoneFiveMilli  int `json:"zone_five_milli,omitempty"`  // Time spent in Heart Rate Zone Five [90-100%).
 		} `json:"zone_duration,omitempty"`
 	} `json:"score,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"` // Fields returned by the API that are not part of the model, keyed by their dotted path.
+}
+
+// UnmarshalJSON unmarshals data into w, keeping unknown fields in w.Extra.
+func (w *Workout) UnmarshalJSON(data []byte) error {
+	type workout Workout
+	var v workout
+	extra, err := unmarshalWithExtra(data, &v)
+	if err != nil {
+		return err
+	}
+	*w = Workout(v)
+	w.Extra = extra
+	return nil
+}
+
+// MarshalJSON marshals w, including the unknown fields in w.Extra.
+func (w Workout) MarshalJSON() ([]byte, error) {
+	type workout Workout
+	return marshalWithExtra(workout(w), w.Extra)
+}
+
+func (w Workout) unknownFields() []string {
+	return extraKeys("", w.Extra)
+}
+
+// Scored reports whether the workout has been scored.
+func (w Workout) Scored() bool {
+	return w.ScoreState != nil && *w.ScoreState == "SCORED"
+}
+
+// Sport returns the name of the sport of the workout, looked up in Sports
+// if the API did not name it, or "Unknown".
+func (w Workout) Sport() string {
+	if w.SportName != nil && *w.SportName != "" {
+		return *w.SportName
+	}
+	if name, ok := Sports[w.SportID]; ok {
+		return name
+	}
+	return "Unknown"
+}
+
+// settled reports whether the workout has been scored.
+func (w Workout) settled() bool {
+	return w.Scored()
 }
 
 // GetOne retrieves a single workout record for the specified id.
@@ -72,6 +120,26 @@ type WorkoutListAllResp struct {
 	NextToken *string   `json:"next_token"`
 }
 
+func (r WorkoutListAllResp) unknownFields() []string {
+	var fields []string
+	for i, w := range r.Records {
+		fields = append(fields, extraKeys(fmt.Sprintf("records[%v].", i), w.Extra)...)
+	}
+	return fields
+}
+
+func (WorkoutListAllResp) page() {}
+
+// settled reports whether every record in the page has settled.
+func (r WorkoutListAllResp) settled() bool {
+	for _, w := range r.Records {
+		if !w.settled() {
+			return false
+		}
+	}
+	return len(r.Records) > 0
+}
+
 // ListAll lists all workout records for the authenticated user.
 // Results are paginated and sorted by start time in descending order.
 //
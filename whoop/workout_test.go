@@ -9,6 +9,25 @@ import (
 	"time"
 )
 
+func TestWorkout_Sport(t *testing.T) {
+	named, empty := "Climbing", ""
+	testCases := []struct {
+		workout Workout
+		want    string
+	}{
+		{Workout{SportID: 0, SportName: &named}, "Climbing"},
+		{Workout{SportID: 0, SportName: &empty}, "Running"},
+		{Workout{SportID: 1}, "Cycling"},
+		{Workout{SportID: -2}, "Unknown"},
+	}
+
+	for _, test := range testCases {
+		if got := test.workout.Sport(); got != test.want {
+			t.Errorf("Workout.Sport(): got %v, want %v", got, test.want)
+		}
+	}
+}
+
 func TestWorkoutService_ListAll(t *testing.T) {
 	client, mux, _, teardown := setup()
 	defer teardown()